@@ -0,0 +1,267 @@
+package upbit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCandlesCount is the largest page size of the candle endpoints.
+	maxCandlesCount = 200
+
+	// candleTimeLayout is the layout of CandleDateTimeUtc and CandleDateTimeKst.
+	candleTimeLayout = "2006-01-02T15:04:05"
+)
+
+// kst is the time zone of CandleDateTimeKst.
+var kst = time.FixedZone("KST", 9*60*60)
+
+// parseCandleTime parses the UTC start time of a candle.
+func parseCandleTime(c Candle) (time.Time, error) {
+	t, err := time.Parse(candleTimeLayout, c.CandleDateTimeUtc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid candle time %q: %w", c.CandleDateTimeUtc, err)
+	}
+	return t, nil
+}
+
+// FetchCandles retrieves the candles of a market starting in [from, to),
+// oldest first. It pages backwards from to, drops duplicates returned at page
+// boundaries and fills intervals without trades with Filled candles that
+// repeat the previous close. Intervals before the first traded candle are
+// not filled.
+func (c *Client) FetchCandles(market string, interval CandleInterval, from, to time.Time) ([]Candle, error) {
+	return c.FetchCandlesContext(context.Background(), market, interval, from, to)
+}
+
+// FetchCandlesContext is like FetchCandles but uses ctx for cancellation and deadlines.
+func (c *Client) FetchCandlesContext(ctx context.Context, market string, interval CandleInterval, from, to time.Time) ([]Candle, error) {
+	candles, err := c.fetchCandleRange(ctx, market, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return fillCandleGaps(nil, candles, interval, to)
+}
+
+// fetchCandleRange pages backwards from to and returns the traded candles
+// starting in [from, to), oldest first and without duplicates.
+func (c *Client) fetchCandleRange(ctx context.Context, market string, interval CandleInterval, from, to time.Time) ([]Candle, error) {
+	if !interval.valid() {
+		return nil, fmt.Errorf("upbit: unknown candle interval %q", interval)
+	}
+
+	seen := make(map[string]bool)
+	var candles []Candle
+	cursor := to
+	for cursor.After(from) {
+		page, err := c.GetCandlesContext(ctx, CandleRequest{
+			Market:   market,
+			Interval: interval,
+			To:       cursor.UTC().Format(time.RFC3339),
+			Count:    maxCandlesCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		oldest := cursor
+		for _, candle := range page {
+			start, err := parseCandleTime(candle)
+			if err != nil {
+				return nil, err
+			}
+			if start.Before(oldest) {
+				oldest = start
+			}
+			if start.Before(from) || !start.Before(to) || seen[candle.CandleDateTimeUtc] {
+				continue
+			}
+			seen[candle.CandleDateTimeUtc] = true
+			candles = append(candles, candle)
+		}
+
+		if len(page) < maxCandlesCount || !oldest.Before(cursor) {
+			break
+		}
+		cursor = oldest
+	}
+
+	slices.SortFunc(candles, func(a, b Candle) int {
+		return strings.Compare(a.CandleDateTimeUtc, b.CandleDateTimeUtc)
+	})
+	return candles, nil
+}
+
+// fillCandleGaps inserts Filled candles for the intervals missing after prev
+// (if non-nil) and between candles. Trailing intervals are filled up to to,
+// but only those that have ended by now.
+func fillCandleGaps(prev *Candle, candles []Candle, interval CandleInterval, to time.Time) ([]Candle, error) {
+	limit := to
+	if now := time.Now(); now.Before(limit) {
+		limit = now
+	}
+
+	filled := make([]Candle, 0, len(candles))
+	var last *Candle
+	if prev != nil {
+		p := *prev
+		last = &p
+	}
+
+	appendGap := func(until time.Time, trailing bool) error {
+		if last == nil {
+			return nil
+		}
+		start, err := parseCandleTime(*last)
+		if err != nil {
+			return err
+		}
+		for slot := interval.advance(start, 1); slot.Before(until); slot = interval.advance(slot, 1) {
+			if trailing && interval.advance(slot, 1).After(limit) {
+				break
+			}
+			filled = append(filled, Candle{
+				Market:            last.Market,
+				CandleDateTimeUtc: slot.Format(candleTimeLayout),
+				CandleDateTimeKst: slot.In(kst).Format(candleTimeLayout),
+				OpeningPrice:      last.TradePrice,
+				HighPrice:         last.TradePrice,
+				LowPrice:          last.TradePrice,
+				TradePrice:        last.TradePrice,
+				Timestamp:         last.Timestamp,
+				Unit:              last.Unit,
+				Filled:            true,
+			})
+		}
+		return nil
+	}
+
+	for i := range candles {
+		start, err := parseCandleTime(candles[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := appendGap(start, false); err != nil {
+			return nil, err
+		}
+		filled = append(filled, candles[i])
+		last = &candles[i]
+	}
+	if err := appendGap(limit, true); err != nil {
+		return nil, err
+	}
+	return filled, nil
+}
+
+// DownloadCandles appends the candles of a market starting in [from, to) to
+// a JSON Lines file, oldest first, with gaps filled as by FetchCandles. If
+// the file already holds candles, from is ignored and the download resumes
+// after the last complete line; a partially written trailing line is
+// discarded. It returns the number of candles written.
+func (c *Client) DownloadCandles(path, market string, interval CandleInterval, from, to time.Time) (int, error) {
+	return c.DownloadCandlesContext(context.Background(), path, market, interval, from, to)
+}
+
+// DownloadCandlesContext is like DownloadCandles but uses ctx for cancellation and deadlines.
+func (c *Client) DownloadCandlesContext(ctx context.Context, path, market string, interval CandleInterval, from, to time.Time) (int, error) {
+	if !interval.valid() {
+		return 0, fmt.Errorf("upbit: unknown candle interval %q", interval)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open candle file: %w", err)
+	}
+	defer f.Close()
+
+	prev, offset, err := lastCandle(f)
+	if err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(offset); err != nil {
+		return 0, fmt.Errorf("failed to truncate candle file: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek candle file: %w", err)
+	}
+
+	start := from
+	if prev != nil {
+		if prev.Market != market {
+			return 0, fmt.Errorf("upbit: candle file holds %s candles, not %s", prev.Market, market)
+		}
+		last, err := parseCandleTime(*prev)
+		if err != nil {
+			return 0, err
+		}
+		start = interval.advance(last, 1)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	written := 0
+	for chunkStart := start; chunkStart.Before(to); {
+		chunkEnd := interval.advance(chunkStart, maxCandlesCount)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+
+		candles, err := c.fetchCandleRange(ctx, market, interval, chunkStart, chunkEnd)
+		if err != nil {
+			return written, err
+		}
+		candles, err = fillCandleGaps(prev, candles, interval, chunkEnd)
+		if err != nil {
+			return written, err
+		}
+
+		for _, candle := range candles {
+			if err := enc.Encode(candle); err != nil {
+				return written, fmt.Errorf("failed to encode candle: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return written, fmt.Errorf("failed to write candle file: %w", err)
+		}
+		written += len(candles)
+
+		if len(candles) > 0 {
+			prev = &candles[len(candles)-1]
+		}
+		chunkStart = chunkEnd
+	}
+	return written, nil
+}
+
+// lastCandle returns the last complete candle of a JSON Lines file and the
+// offset just past it.
+func lastCandle(f *os.File) (*Candle, int64, error) {
+	r := bufio.NewReader(f)
+	var last *Candle
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Anything after the last newline is an interrupted write.
+			return last, offset, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read candle file: %w", err)
+		}
+
+		var candle Candle
+		if err := json.Unmarshal(line, &candle); err != nil {
+			return nil, 0, fmt.Errorf("invalid candle at offset %d: %w", offset, err)
+		}
+		last = &candle
+		offset += int64(len(line))
+	}
+}
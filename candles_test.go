@@ -0,0 +1,182 @@
+package upbit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newCandleServer serves minute candles starting at start, one per minute,
+// except for the minutes in missing. Like Upbit at page boundaries, the
+// candle starting exactly at "to" is returned too.
+func newCandleServer(t *testing.T, start time.Time, n int, missing map[int]bool) (*httptest.Server, *int) {
+	t.Helper()
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+		if err != nil {
+			t.Errorf("Invalid to %q", r.URL.Query().Get("to"))
+		}
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+		candles := []Candle{}
+		for i := n - 1; i >= 0 && len(candles) < count; i-- {
+			ts := start.Add(time.Duration(i) * time.Minute)
+			if missing[i] || ts.After(to) {
+				continue
+			}
+			candles = append(candles, Candle{
+				Market:            "KRW-BTC",
+				CandleDateTimeUtc: ts.Format(candleTimeLayout),
+				TradePrice:        DecimalFromInt(int64(i)),
+			})
+		}
+		json.NewEncoder(w).Encode(candles)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestFetchCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server, requests := newCandleServer(t, start, 450, map[int]bool{0: true, 100: true, 101: true, 449: true})
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRateLimiter(nil)
+
+	candles, err := client.FetchCandles("KRW-BTC", CandleIntervalMinute1, start, start.Add(450*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", *requests)
+	}
+
+	// Minute 0 precedes the first trade and stays missing; the others are
+	// filled from the previous close.
+	if len(candles) != 449 {
+		t.Fatalf("Expected 449 candles, got %d", len(candles))
+	}
+	for i, c := range candles {
+		want := start.Add(time.Duration(i+1) * time.Minute).Format(candleTimeLayout)
+		if c.CandleDateTimeUtc != want {
+			t.Fatalf("Candle %d: expected %s, got %s", i, want, c.CandleDateTimeUtc)
+		}
+	}
+	for _, i := range []int{100, 101} {
+		if c := candles[i-1]; !c.Filled || c.TradePrice.String() != "99" {
+			t.Errorf("Minute %d: expected a filled candle at 99, got %+v", i, c)
+		}
+	}
+	if c := candles[448]; !c.Filled || c.TradePrice.String() != "448" {
+		t.Errorf("Expected the trailing gap to repeat 448, got %+v", c)
+	}
+	if candles[1].Filled {
+		t.Error("Expected traded candles not to be marked filled")
+	}
+}
+
+func TestDownloadCandlesResumes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server, _ := newCandleServer(t, start, 300, map[int]bool{150: true})
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRateLimiter(nil)
+
+	path := filepath.Join(t.TempDir(), "candles.jsonl")
+	n, err := client.DownloadCandles(path, "KRW-BTC", CandleIntervalMinute1, start, start.Add(120*time.Minute))
+	if err != nil || n != 120 {
+		t.Fatalf("Expected 120 candles, got %d (%v)", n, err)
+	}
+
+	// Simulate a write interrupted mid-line.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"market":"KRW-BTC","candle_date`)
+	f.Close()
+
+	n, err = client.DownloadCandles(path, "KRW-BTC", CandleIntervalMinute1, start, start.Add(300*time.Minute))
+	if err != nil || n != 180 {
+		t.Fatalf("Expected 180 more candles, got %d (%v)", n, err)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	i := 0
+	for scanner.Scan() {
+		var c Candle
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			t.Fatalf("Line %d: %v", i, err)
+		}
+		if want := start.Add(time.Duration(i) * time.Minute).Format(candleTimeLayout); c.CandleDateTimeUtc != want {
+			t.Fatalf("Line %d: expected %s, got %s", i, want, c.CandleDateTimeUtc)
+		}
+		if c.Filled != (i == 150) {
+			t.Errorf("Line %d: unexpected Filled %v", i, c.Filled)
+		}
+		i++
+	}
+	if i != 300 {
+		t.Errorf("Expected 300 lines, got %d", i)
+	}
+}
+
+func TestGetCandlesEndpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		unit := r.URL.Query().Get("converting_price_unit")
+		if r.URL.Path == "/v1/candles/days" && unit != "KRW" || r.URL.Path != "/v1/candles/days" && unit != "" {
+			t.Errorf("Unexpected converting_price_unit %q for %s", unit, r.URL.Path)
+		}
+		w.Write([]byte(`[{"candle_date_time_utc":"2024-01-02T00:00:00"},{"candle_date_time_utc":"2024-01-01T00:00:00"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	for _, interval := range []CandleInterval{CandleIntervalSecond, CandleIntervalMinute240, CandleIntervalDay, CandleIntervalYear} {
+		candles, err := client.GetCandles(CandleRequest{
+			Market:              "KRW-BTC",
+			Interval:            interval,
+			Count:               2,
+			ConvertingPriceUnit: "KRW",
+		})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", interval, err)
+		}
+		if candles[0].CandleDateTimeUtc != "2024-01-01T00:00:00" {
+			t.Errorf("%s: expected candles oldest first, got %+v", interval, candles)
+		}
+	}
+
+	want := []string{"/v1/candles/seconds", "/v1/candles/minutes/240", "/v1/candles/days", "/v1/candles/years"}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+
+	if _, err := client.GetCandlesContext(context.Background(), CandleRequest{Market: "KRW-BTC", Interval: "minutes/0"}); err == nil {
+		t.Error("Expected an error for an unknown interval")
+	}
+	if _, err := client.GetMinuteCandles("KRW-BTC", CandleUnit3, "", 1); err != nil || paths[len(paths)-1] != "/v1/candles/minutes/3" {
+		t.Errorf("Expected GetMinuteCandles to delegate to /candles/minutes/3, got %v %v", paths, err)
+	}
+}
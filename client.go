@@ -2,14 +2,20 @@
 package upbit
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -17,7 +23,7 @@ import (
 )
 
 const (
-	BaseURL = "https://api.upbit.com/v1"
+	BaseURL = "https://api.upbit.com/v1" // REST API base URL of RegionKR
 )
 
 // Client is the main Upbit API client.
@@ -25,22 +31,83 @@ type Client struct {
 	accessKey  string
 	secretKey  string
 	httpClient *http.Client
+	region     Region
 	baseURL    string
+	wsURL      string
+	limiter    *RateLimiter
+	retry      RetryPolicy
+	userAgent  string
+	logger     *slog.Logger
+	transport  RoundTripFunc
+
+	bodyLogging map[RateLimitGroup]bool
+	hooks       []Hook
+
+	// configErr, if set, fails every request of a misconfigured client.
+	configErr error
+
+	validateOrders bool
+	dryRun         atomic.Pointer[dryRun]
 }
 
-// NewClient creates a new Upbit API client.
+// NewClient creates a new Upbit API client configured by opts.
 // For public API endpoints, you can pass empty strings for accessKey and secretKey.
-func NewClient(accessKey, secretKey string) *Client {
-	return &Client{
-		accessKey: accessKey,
-		secretKey: secretKey,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseURL: BaseURL,
+func NewClient(accessKey, secretKey string, opts ...Option) *Client {
+	o := options{
+		region:  RegionKR,
+		limiter: NewRateLimiter(),
+		retry:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.baseURL == "" {
+		o.baseURL = o.region.BaseURL()
+	}
+	if o.wsURL == "" {
+		o.wsURL = o.region.WebSocketURL()
 	}
+
+	httpClient := o.httpClient
+	switch {
+	case httpClient == nil:
+		httpClient = &http.Client{Timeout: defaultTimeout}
+		if o.timeout > 0 {
+			httpClient.Timeout = o.timeout
+		}
+	case o.timeout > 0:
+		copied := *httpClient
+		copied.Timeout = o.timeout
+		httpClient = &copied
+	}
+
+	c := &Client{
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: httpClient,
+		region:     o.region,
+		baseURL:    o.baseURL,
+		wsURL:      o.wsURL,
+		limiter:    o.limiter,
+		retry:      o.retry,
+		userAgent:  o.userAgent,
+		logger:     o.logger,
+
+		bodyLogging: o.bodyLogging,
+		hooks:       o.hooks,
+	}
+	if !o.region.IsValid() {
+		c.configErr = fmt.Errorf("upbit: unknown region %q", o.region)
+	}
+	c.transport = chain(func(req *http.Request, _ RequestInfo) (*http.Response, error) {
+		return c.httpClient.Do(req)
+	}, o.middleware)
+	return c
 }
 
+// The setters below must not be called while the client is in use; prefer
+// the equivalent options to NewClient.
+
 // SetHTTPClient allows you to set a custom HTTP client.
 func (c *Client) SetHTTPClient(client *http.Client) {
 	c.httpClient = client
@@ -51,6 +118,45 @@ func (c *Client) SetBaseURL(baseURL string) {
 	c.baseURL = baseURL
 }
 
+// SetWebSocketURL allows you to set a custom WebSocket URL (useful for testing).
+func (c *Client) SetWebSocketURL(wsURL string) {
+	c.wsURL = wsURL
+}
+
+// SetRateLimiter replaces the client's rate limiter. Passing nil disables
+// client-side throttling.
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.limiter = limiter
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// SetOrderValidation enables or disables client-side validation in
+// PlaceOrder. When enabled, each order is checked with ValidateOrder against
+// the market's order chance before it is sent, and rejected orders fail
+// with *OrderValidationError. Incompatible time in force and self-match
+// prevention options are rejected even when validation is disabled.
+func (c *Client) SetOrderValidation(enabled bool) {
+	c.validateOrders = enabled
+}
+
+// Region returns the Upbit exchange the client was created for.
+func (c *Client) Region() Region {
+	return c.region
+}
+
+// RemainingRequests returns the request quota last reported by Upbit for a
+// rate limit group.
+func (c *Client) RemainingRequests(group RateLimitGroup) (RemainingReq, bool) {
+	if c.limiter == nil {
+		return RemainingReq{}, false
+	}
+	return c.limiter.Remaining(group)
+}
+
 // generateToken creates a JWT token for authenticated requests.
 func (c *Client) generateToken(queryParams url.Values) (string, error) {
 	claims := jwt.MapClaims{
@@ -60,8 +166,7 @@ func (c *Client) generateToken(queryParams url.Values) (string, error) {
 	}
 
 	if len(queryParams) > 0 {
-		queryString := queryParams.Encode()
-		hash := sha512.Sum512([]byte(queryString))
+		hash := sha512.Sum512([]byte(canonicalQuery(queryParams)))
 		claims["query_hash"] = hex.EncodeToString(hash[:])
 		claims["query_hash_alg"] = "SHA512"
 	}
@@ -70,68 +175,259 @@ func (c *Client) generateToken(queryParams url.Values) (string, error) {
 	return token.SignedString([]byte(c.secretKey))
 }
 
-// doRequest performs an HTTP request.
-func (c *Client) doRequest(method, endpoint string, params url.Values, authenticated bool) ([]byte, error) {
-	urlStr := c.baseURL + endpoint
+// canonicalQuery returns params in the unescaped "key=value&..." form that
+// Upbit recomputes the query_hash over. Keys are sorted and repeated keys
+// such as "uuids[]" keep their value order.
+func canonicalQuery(params url.Values) string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		for _, v := range params[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(key)
+			b.WriteByte('=')
+			b.WriteString(v)
+		}
+	}
+	return b.String()
+}
+
+// paramEncoding selects how request parameters are sent.
+type paramEncoding int
+
+const (
+	encodeQuery paramEncoding = iota // URL query string
+	encodeJSON                       // JSON request body
+)
+
+// encodeJSONBody encodes params as a JSON object in canonicalQuery order.
+// Array keys like "uuids[]" become JSON arrays named without the brackets.
+func encodeJSONBody(params url.Values) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range slices.Sorted(maps.Keys(params)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, isArray := strings.CutSuffix(key, "[]")
+		k, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		var v []byte
+		if isArray {
+			v, err = json.Marshal(params[key])
+		} else {
+			v, err = json.Marshal(params.Get(key))
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// request describes a single API call.
+type request struct {
+	method        string
+	endpoint      string
+	params        url.Values
+	encoding      paramEncoding
+	authenticated bool
+	idempotent    bool // safe to retry on transient failures
+
+	// beforeRetry, if set, runs before each retry. Returning done ends the
+	// call with resp instead of resending the request.
+	beforeRetry func(ctx context.Context) (resp *response, done bool, err error)
+}
+
+// response is the raw outcome of an HTTP exchange.
+type response struct {
+	body       []byte
+	statusCode int
+	header     http.Header
+}
+
+// doRequest performs an HTTP request bound to ctx. GET requests are retried
+// according to the client's retry policy.
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, params url.Values, encoding paramEncoding, authenticated bool) ([]byte, error) {
+	return c.send(ctx, &request{
+		method:        method,
+		endpoint:      endpoint,
+		params:        params,
+		encoding:      encoding,
+		authenticated: authenticated,
+		idempotent:    method == http.MethodGet,
+	})
+}
+
+// info describes attempt number attempt of r to middleware and hooks.
+func (r *request) info(attempt int) RequestInfo {
+	return RequestInfo{
+		Method:        r.method,
+		Endpoint:      r.endpoint,
+		Authenticated: r.authenticated,
+		Group:         rateLimitGroupFor(r.method, r.endpoint),
+		Attempt:       attempt,
+	}
+}
+
+// send performs r, retrying transient failures when r is idempotent.
+func (c *Client) send(ctx context.Context, r *request) ([]byte, error) {
+	resp, err := c.roundTrip(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+// roundTrip is like send but returns the final response.
+func (c *Client) roundTrip(ctx context.Context, r *request) (*response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	if err := c.blockedByDryRun(r); err != nil {
+		return nil, err
+	}
+	return c.observe(ctx, r)
+}
+
+// sendAttempts performs r until it succeeds or may not be retried. It
+// returns the response the call ended with, which on failure is the last
+// one received if any, and the number of attempts.
+func (c *Client) sendAttempts(ctx context.Context, r *request) (*response, int, error) {
+	policy := c.retry
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, r, attempt)
+		if err == nil {
+			return resp, attempt, nil
+		}
+		if !r.idempotent || attempt >= policy.MaxAttempts || !shouldRetry(ctx, err) {
+			return resp, attempt, err
+		}
+
+		var retryAfter time.Duration
+		if resp != nil {
+			retryAfter = parseRetryAfter(resp.header.Get("Retry-After"), time.Now())
+		}
+		delay := policy.backoff(attempt, retryAfter)
+		if c.logger != nil {
+			c.logger.LogAttrs(ctx, slog.LevelWarn, "upbit: retrying request",
+				slog.String("method", r.method),
+				slog.String("endpoint", r.endpoint),
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay),
+				slog.String("error", c.redactError(err)))
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return resp, attempt, err
+		}
+
+		if r.beforeRetry != nil {
+			found, done, lookupErr := r.beforeRetry(ctx)
+			if lookupErr != nil {
+				return resp, attempt, fmt.Errorf("failed to check request before retrying: %w (after %w)", lookupErr, err)
+			}
+			if done {
+				return found, attempt, nil
+			}
+		}
+	}
+}
+
+// attempt performs a single HTTP exchange through the client's middleware.
+// The returned response is nil if no response was received.
+func (c *Client) attempt(ctx context.Context, r *request, attempt int) (*response, error) {
+	urlStr := c.baseURL + r.endpoint
 	var body io.Reader
 
-	if method == http.MethodGet && len(params) > 0 {
-		urlStr += "?" + params.Encode()
-	} else if len(params) > 0 {
-		body = strings.NewReader(params.Encode())
+	if len(r.params) > 0 {
+		switch r.encoding {
+		case encodeJSON:
+			data, err := encodeJSONBody(r.params)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		default:
+			urlStr += "?" + r.params.Encode()
+		}
 	}
 
-	req, err := http.NewRequest(method, urlStr, body)
+	req, err := http.NewRequestWithContext(ctx, r.method, urlStr, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	if method == http.MethodPost || method == http.MethodDelete {
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	info := r.info(attempt)
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx, info.Group); err != nil {
+			return nil, err
+		}
 	}
 
-	if authenticated {
-		token, err := c.generateToken(params)
+	// The token is signed after waiting for the limiter so that its
+	// timestamp reflects when the request is actually sent.
+	if r.authenticated {
+		token, err := c.generateToken(r.params)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate token: %w", err)
 		}
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	start := time.Now()
+	httpResp, err := c.transport(req, info)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		err = fmt.Errorf("failed to execute request: %w", err)
+		c.logExchange(ctx, r, info, req, nil, time.Since(start), err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	defer httpResp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if c.limiter != nil {
+		c.limiter.observe(httpResp.Header)
 	}
 
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &apiErr
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		err = fmt.Errorf("failed to read response: %w", err)
+		c.logExchange(ctx, r, info, req, nil, time.Since(start), err)
+		return nil, err
 	}
 
-	return respBody, nil
+	resp := &response{body: respBody, statusCode: httpResp.StatusCode, header: httpResp.Header}
+	if resp.statusCode >= 400 {
+		err = newAPIError(r, resp)
+	}
+	c.logExchange(ctx, r, info, req, resp, time.Since(start), err)
+	return resp, err
 }
 
-// get performs a GET request.
-func (c *Client) get(endpoint string, params url.Values, authenticated bool) ([]byte, error) {
-	return c.doRequest(http.MethodGet, endpoint, params, authenticated)
+// get performs a GET request with parameters in the query string.
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, authenticated bool) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodGet, endpoint, params, encodeQuery, authenticated)
 }
 
-// post performs a POST request.
-func (c *Client) post(endpoint string, params url.Values, authenticated bool) ([]byte, error) {
-	return c.doRequest(http.MethodPost, endpoint, params, authenticated)
+// post performs a POST request with parameters in a JSON body.
+func (c *Client) post(ctx context.Context, endpoint string, params url.Values, authenticated bool) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodPost, endpoint, params, encodeJSON, authenticated)
 }
 
-// delete performs a DELETE request.
-func (c *Client) delete(endpoint string, params url.Values, authenticated bool) ([]byte, error) {
-	return c.doRequest(http.MethodDelete, endpoint, params, authenticated)
+// delete performs a DELETE request with parameters in the query string.
+func (c *Client) delete(ctx context.Context, endpoint string, params url.Values, authenticated bool) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodDelete, endpoint, params, encodeQuery, authenticated)
 }
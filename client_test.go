@@ -1,10 +1,19 @@
 package upbit
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -65,7 +74,7 @@ func TestGetTicker(t *testing.T) {
 		tickers := []Ticker{
 			{
 				Market:     "KRW-BTC",
-				TradePrice: 50000000,
+				TradePrice: DecimalFromInt(50000000),
 				Change:     "RISE",
 			},
 		}
@@ -85,8 +94,8 @@ func TestGetTicker(t *testing.T) {
 		t.Errorf("Expected 1 ticker, got %d", len(tickers))
 	}
 
-	if tickers[0].TradePrice != 50000000 {
-		t.Errorf("Expected trade price 50000000, got %f", tickers[0].TradePrice)
+	if !tickers[0].TradePrice.Equal(DecimalFromInt(50000000)) {
+		t.Errorf("Expected trade price 50000000, got %s", tickers[0].TradePrice)
 	}
 }
 
@@ -99,10 +108,15 @@ func TestGetOrderbook(t *testing.T) {
 		orderbooks := []Orderbook{
 			{
 				Market:       "KRW-BTC",
-				TotalAskSize: 10.5,
-				TotalBidSize: 8.3,
+				TotalAskSize: MustParseDecimal("10.5"),
+				TotalBidSize: MustParseDecimal("8.3"),
 				OrderbookUnits: []OrderbookUnit{
-					{AskPrice: 50100000, BidPrice: 50000000, AskSize: 1.5, BidSize: 2.0},
+					{
+						AskPrice: DecimalFromInt(50100000),
+						BidPrice: DecimalFromInt(50000000),
+						AskSize:  MustParseDecimal("1.5"),
+						BidSize:  MustParseDecimal("2.0"),
+					},
 				},
 			},
 		}
@@ -122,8 +136,8 @@ func TestGetOrderbook(t *testing.T) {
 		t.Errorf("Expected 1 orderbook, got %d", len(orderbooks))
 	}
 
-	if orderbooks[0].TotalAskSize != 10.5 {
-		t.Errorf("Expected total ask size 10.5, got %f", orderbooks[0].TotalAskSize)
+	if !orderbooks[0].TotalAskSize.Equal(MustParseDecimal("10.5")) {
+		t.Errorf("Expected total ask size 10.5, got %s", orderbooks[0].TotalAskSize)
 	}
 }
 
@@ -150,6 +164,88 @@ func TestAPIError(t *testing.T) {
 	if apiErr.Err.Name != "invalid_parameter" {
 		t.Errorf("Expected error name 'invalid_parameter', got '%s'", apiErr.Err.Name)
 	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Method != http.MethodGet || apiErr.Endpoint != "/ticker" {
+		t.Errorf("Unexpected request details: %d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.Endpoint)
+	}
+	if !errors.Is(err, ErrInvalidParameter) || errors.Is(err, ErrInvalidMarket) {
+		t.Error("Expected errors.Is to match only invalid_parameter")
+	}
+}
+
+func TestAPIErrorNonJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Remaining-Req", "group=order; min=59; sec=0")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>502 Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+	_, err := client.GetAccounts()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Err.Code() != ErrServerError {
+		t.Errorf("Expected server_error, got %s", apiErr.Err.Name)
+	}
+	if string(apiErr.Body) != "<html>502 Bad Gateway</html>" {
+		t.Errorf("Unexpected body %q", apiErr.Body)
+	}
+	rr, ok := apiErr.RemainingReq()
+	if !ok || rr.Group != RateLimitGroupOrder || rr.Sec != 0 {
+		t.Errorf("Unexpected Remaining-Req %+v", rr)
+	}
+	if !IsRetryable(err) || IsAuthError(err) {
+		t.Error("Expected a retryable, non-auth error")
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	apiErr := func(status int, name ErrorCode) error {
+		return fmt.Errorf("failed to place order: %w", &APIError{StatusCode: status, Err: ErrorDetail{Name: string(name)}})
+	}
+	tests := []struct {
+		name                     string
+		err                      error
+		retryable, auth, noFunds bool
+	}{
+		{"rate limited", apiErr(http.StatusTooManyRequests, ErrTooManyRequests), true, false, false},
+		{"server error", apiErr(http.StatusInternalServerError, ErrServerError), true, false, false},
+		{"expired key", apiErr(http.StatusUnauthorized, ErrExpiredAccessKey), false, true, false},
+		{"ip not allowed", apiErr(http.StatusUnauthorized, ErrNoAuthorizationIP), false, true, false},
+		{"insufficient bid", apiErr(http.StatusBadRequest, ErrInsufficientFundsBid), false, false, true},
+		{"validation", invalidOrder(ErrInsufficientFunds, "Volume", "too much"), false, false, true},
+		{"invalid price", invalidOrder(ErrInvalidPrice, "Price", "off tick"), false, false, false},
+		{"cancelled", context.Canceled, false, false, false},
+		{"connection reset", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, true, false, false},
+		{"connection refused", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true, false, false},
+		{"timeout", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: os.ErrDeadlineExceeded}, true, false, false},
+		{"truncated", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: io.ErrUnexpectedEOF}, true, false, false},
+		{"dns", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: &net.DNSError{Err: "no such host", Name: "api.upbit.com", IsNotFound: true}}, false, false, false},
+		{"tls", &url.Error{Op: "Get", URL: "https://api.upbit.com", Err: errors.New("tls: failed to verify certificate")}, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("%s: IsRetryable = %v, want %v", tt.name, got, tt.retryable)
+		}
+		if got := IsAuthError(tt.err); got != tt.auth {
+			t.Errorf("%s: IsAuthError = %v, want %v", tt.name, got, tt.auth)
+		}
+		if got := IsInsufficientFunds(tt.err); got != tt.noFunds {
+			t.Errorf("%s: IsInsufficientFunds = %v, want %v", tt.name, got, tt.noFunds)
+		}
+	}
+
+	if !errors.Is(invalidOrder(ErrInvalidPrice, "Price", "off tick"), ErrInvalidPrice) {
+		t.Error("Expected OrderValidationError to match its code")
+	}
 }
 
 func TestGenerateToken(t *testing.T) {
@@ -177,8 +273,8 @@ func TestGetAccounts(t *testing.T) {
 		}
 
 		accounts := []Account{
-			{Currency: "KRW", Balance: "1000000", Locked: "0"},
-			{Currency: "BTC", Balance: "0.1", Locked: "0"},
+			{Currency: "KRW", Balance: DecimalFromInt(1000000)},
+			{Currency: "BTC", Balance: MustParseDecimal("0.1")},
 		}
 		json.NewEncoder(w).Encode(accounts)
 	}))
@@ -212,8 +308,8 @@ func TestPlaceOrder(t *testing.T) {
 			Side:    "bid",
 			OrdType: "limit",
 			State:   "wait",
-			Volume:  "0.0001",
-			Price:   "50000000",
+			Volume:  MustParseDecimal("0.0001"),
+			Price:   DecimalFromInt(50000000),
 		}
 		json.NewEncoder(w).Encode(order)
 	}))
@@ -225,8 +321,8 @@ func TestPlaceOrder(t *testing.T) {
 	order, err := client.PlaceOrder(&PlaceOrderRequest{
 		Market:  "KRW-BTC",
 		Side:    OrderSideBid,
-		Volume:  "0.0001",
-		Price:   "50000000",
+		Volume:  MustParseDecimal("0.0001"),
+		Price:   DecimalFromInt(50000000),
 		OrdType: OrderTypeLimit,
 	})
 	if err != nil {
@@ -238,6 +334,42 @@ func TestPlaceOrder(t *testing.T) {
 	}
 }
 
+func TestPlaceOrderSMP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["smp_type"] != "reduce" {
+			t.Errorf("Expected smp_type 'reduce', got '%s'", body["smp_type"])
+		}
+
+		w.Write([]byte(`{"uuid":"test-uuid","market":"KRW-BTC","side":"bid","ord_type":"limit","state":"wait",` +
+			`"volume":"0.0001","price":"50000000","smp_type":"reduce","prevented_volume":"0.00004","prevented_locked":"2001"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	order, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		Volume:  MustParseDecimal("0.0001"),
+		Price:   DecimalFromInt(50000000),
+		OrdType: OrderTypeLimit,
+		SMPType: SMPTypeReduce,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if order.SMPType != "reduce" {
+		t.Errorf("Expected smp_type 'reduce', got '%s'", order.SMPType)
+	}
+	if order.PreventedVolume.String() != "0.00004" || order.PreventedLocked.String() != "2001" {
+		t.Errorf("Expected prevented 0.00004/2001, got %s/%s", order.PreventedVolume, order.PreventedLocked)
+	}
+}
+
 func TestCancelOrder(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
@@ -265,15 +397,134 @@ func TestCancelOrder(t *testing.T) {
 	}
 }
 
+func TestReplaceOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/orders/cancel_and_new":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["prev_order_uuid"] != "old-uuid" || body["new_ord_type"] != "limit" || body["new_volume"] != "remain_only" || body["new_price"] != "49000000" {
+				t.Errorf("Unexpected body %v", body)
+			}
+			w.Write([]byte(`{"uuid":"old-uuid","state":"wait","new_order_uuid":"new-uuid"}`))
+		case "/v1/order":
+			if r.URL.Query().Get("uuid") != "new-uuid" {
+				t.Errorf("Expected lookup of new-uuid, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"uuid":"new-uuid","state":"wait","price":"49000000","trades":[]}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	result, err := client.ReplaceOrder(&ReplaceOrderRequest{
+		PrevOrderUUID:   "old-uuid",
+		NewOrdType:      OrderTypeLimit,
+		NewPrice:        DecimalFromInt(49000000),
+		RemainingVolume: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Cancelled.UUID != "old-uuid" || result.New.UUID != "new-uuid" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	if _, err := client.ReplaceOrder(&ReplaceOrderRequest{NewOrdType: OrderTypeLimit}); err == nil {
+		t.Error("Expected an error without a previous order")
+	}
+}
+
+func TestCancelOrdersBatches(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v1/orders/uuids" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		uuids := r.URL.Query()["uuids[]"]
+		batches = append(batches, len(uuids))
+
+		result := CancelOrdersResult{}
+		for _, id := range uuids {
+			if id == "uuid-3" {
+				result.Failed.Orders = append(result.Failed.Orders, CancelledOrder{UUID: id})
+			} else {
+				result.Success.Orders = append(result.Success.Orders, CancelledOrder{UUID: id})
+			}
+		}
+		result.Success.Count = len(result.Success.Orders)
+		result.Failed.Count = len(result.Failed.Orders)
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	var uuids []string
+	for i := range 25 {
+		uuids = append(uuids, fmt.Sprintf("uuid-%d", i))
+	}
+	result, err := client.CancelOrders(uuids)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(batches) != 2 || batches[0] != 20 || batches[1] != 5 {
+		t.Errorf("Expected batches of 20 and 5, got %v", batches)
+	}
+	if result.Success.Count != 24 || len(result.Success.Orders) != 24 {
+		t.Errorf("Expected 24 successes, got %+v", result.Success)
+	}
+	if result.Failed.Count != 1 || result.Failed.Orders[0].UUID != "uuid-3" {
+		t.Errorf("Expected uuid-3 to fail, got %+v", result.Failed)
+	}
+}
+
+func TestCancelOpenOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "cancel_side=bid&count=50&excluded_pairs=KRW-ETH&quote_currencies=KRW"
+		if got, _ := url.QueryUnescape(r.URL.RawQuery); got != want {
+			t.Errorf("Expected query %s, got %s", want, got)
+		}
+		w.Write([]byte(`{"success":{"count":1,"orders":[{"uuid":"a","market":"KRW-BTC"}]},"failed":{"count":0,"orders":[]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	result, err := client.CancelOpenOrders(&CancelOpenOrdersRequest{
+		Side:            OrderSideBid,
+		QuoteCurrencies: []string{"KRW"},
+		ExcludedMarkets: []string{"KRW-ETH"},
+		Count:           50,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Success.Count != 1 || result.Success.Orders[0].Market != "KRW-BTC" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	_, err = client.CancelOpenOrders(&CancelOpenOrdersRequest{Markets: []string{"KRW-BTC"}, QuoteCurrencies: []string{"KRW"}})
+	if err == nil {
+		t.Error("Expected an error when combining Markets and QuoteCurrencies")
+	}
+}
+
 func TestGetCandles(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		candles := []Candle{
 			{
 				Market:       "KRW-BTC",
-				OpeningPrice: 50000000,
-				HighPrice:    51000000,
-				LowPrice:     49000000,
-				TradePrice:   50500000,
+				OpeningPrice: DecimalFromInt(50000000),
+				HighPrice:    DecimalFromInt(51000000),
+				LowPrice:     DecimalFromInt(49000000),
+				TradePrice:   DecimalFromInt(50500000),
 			},
 		}
 		json.NewEncoder(w).Encode(candles)
@@ -310,3 +561,24 @@ func TestGetCandles(t *testing.T) {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 }
+
+func TestContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetTickerContext(ctx, []string{"KRW-BTC"})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
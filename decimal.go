@@ -0,0 +1,340 @@
+package upbit
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DivisionPrecision is the number of decimal places kept by Decimal.Div.
+var DivisionPrecision int32 = 16
+
+// maxParseExp bounds the exponent accepted by ParseDecimal, so that input
+// such as "1e-2000000000" cannot force huge allocations in later arithmetic
+// or formatting.
+const maxParseExp = 10000
+
+// Decimal is an arbitrary-precision decimal number used for prices, volumes
+// and balances. The zero value is 0. Decimals are immutable; arithmetic
+// methods return new values.
+//
+// Decimals marshal to JSON strings and unmarshal from JSON strings or numbers
+// without passing through float64.
+type Decimal struct {
+	coef *big.Int // unscaled value; nil means zero
+	exp  int32    // the value is coef * 10^exp
+}
+
+var bigTen = big.NewInt(10)
+
+// pow10 returns 10^n for n >= 0.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// NewDecimal returns value * 10^exp.
+func NewDecimal(value int64, exp int32) Decimal {
+	return Decimal{coef: big.NewInt(value), exp: exp}
+}
+
+// DecimalFromInt returns an integer Decimal.
+func DecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// DecimalFromFloat returns the Decimal with the shortest representation
+// that round-trips to f. It panics if f is NaN or infinite.
+func DecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		panic(fmt.Sprintf("upbit: cannot convert %v to Decimal", f))
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "123.45", "-0.00000001" or
+// "1.5e-3". Values with a scale beyond 10^±10000 are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("upbit: invalid decimal %q", orig)
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	sign := ""
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		sign, digits = digits[:1], digits[1:]
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("upbit: invalid decimal %q", orig)
+	}
+
+	coef, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("upbit: invalid decimal %q", orig)
+	}
+	exp -= int64(len(fracPart))
+	if exp < -maxParseExp || exp > maxParseExp {
+		return Decimal{}, fmt.Errorf("upbit: decimal exponent out of range %q", orig)
+	}
+	return Decimal{coef: coef, exp: int32(exp)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// align returns the coefficients of d and o scaled to a common exponent.
+func align(d, o Decimal) (dc, oc *big.Int, exp int32) {
+	dc, oc = d.coefficient(), o.coefficient()
+	switch {
+	case d.exp > o.exp:
+		return new(big.Int).Mul(dc, pow10(d.exp-o.exp)), oc, o.exp
+	case d.exp < o.exp:
+		return dc, new(big.Int).Mul(oc, pow10(o.exp-d.exp)), d.exp
+	default:
+		return dc, oc, d.exp
+	}
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	dc, oc, exp := align(d, o)
+	return Decimal{coef: new(big.Int).Add(dc, oc), exp: exp}
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	dc, oc, exp := align(d, o)
+	return Decimal{coef: new(big.Int).Sub(dc, oc), exp: exp}
+}
+
+// Mul returns d * o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.coefficient(), o.coefficient()), exp: d.exp + o.exp}
+}
+
+// Div returns d / o rounded to DivisionPrecision decimal places. It panics
+// if o is zero.
+func (d Decimal) Div(o Decimal) Decimal {
+	return d.DivRound(o, DivisionPrecision)
+}
+
+// DivRound returns d / o rounded half away from zero to the given number of
+// decimal places. It panics if o is zero.
+func (d Decimal) DivRound(o Decimal, places int32) Decimal {
+	if o.IsZero() {
+		panic("upbit: decimal division by zero")
+	}
+	num := new(big.Int).Set(d.coefficient())
+	den := new(big.Int).Set(o.coefficient())
+	if k := d.exp - o.exp + places; k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+	return Decimal{coef: quoRound(num, den, roundHalfAway), exp: -places}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), exp: d.exp}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), exp: d.exp}
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and o, returning -1, 0 or 1.
+func (d Decimal) Cmp(o Decimal) int {
+	dc, oc, _ := align(d, o)
+	return dc.Cmp(oc)
+}
+
+// Equal reports whether d == o.
+func (d Decimal) Equal(o Decimal) bool { return d.Cmp(o) == 0 }
+
+// LessThan reports whether d < o.
+func (d Decimal) LessThan(o Decimal) bool { return d.Cmp(o) < 0 }
+
+// GreaterThan reports whether d > o.
+func (d Decimal) GreaterThan(o Decimal) bool { return d.Cmp(o) > 0 }
+
+// roundingMode selects how discarded digits are handled.
+type roundingMode int
+
+const (
+	roundHalfAway roundingMode = iota // half away from zero
+	roundFloor                        // toward negative infinity
+	roundCeil                         // toward positive infinity
+	roundTruncate                     // toward zero
+)
+
+// quoRound returns num / den rounded according to mode.
+func quoRound(num, den *big.Int, mode roundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	// The sign of the exact quotient.
+	sign := r.Sign() * den.Sign()
+	switch mode {
+	case roundHalfAway:
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+		if twice.Cmp(new(big.Int).Abs(den)) >= 0 {
+			q.Add(q, big.NewInt(int64(sign)))
+		}
+	case roundFloor:
+		if sign < 0 {
+			q.Sub(q, big.NewInt(1))
+		}
+	case roundCeil:
+		if sign > 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func (d Decimal) round(places int32, mode roundingMode) Decimal {
+	if d.exp >= -places {
+		return d
+	}
+	q := quoRound(d.coefficient(), pow10(-places-d.exp), mode)
+	return Decimal{coef: q, exp: -places}
+}
+
+// Round rounds d half away from zero to the given number of decimal places.
+// Negative places round to tens, hundreds, and so on.
+func (d Decimal) Round(places int32) Decimal { return d.round(places, roundHalfAway) }
+
+// Floor rounds d toward negative infinity to the given number of decimal places.
+func (d Decimal) Floor(places int32) Decimal { return d.round(places, roundFloor) }
+
+// Ceil rounds d toward positive infinity to the given number of decimal places.
+func (d Decimal) Ceil(places int32) Decimal { return d.round(places, roundCeil) }
+
+// Truncate rounds d toward zero to the given number of decimal places.
+func (d Decimal) Truncate(places int32) Decimal { return d.round(places, roundTruncate) }
+
+// String returns d in plain notation without trailing fractional zeros.
+func (d Decimal) String() string {
+	coef := d.coefficient()
+	if coef.Sign() == 0 {
+		return "0"
+	}
+	if d.exp >= 0 {
+		return new(big.Int).Mul(coef, pow10(d.exp)).String()
+	}
+
+	digits := new(big.Int).Abs(coef).String()
+	scale := int(-d.exp)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-scale], strings.TrimRight(digits[len(digits)-scale:], "0")
+
+	s := intPart
+	if fracPart != "" {
+		s += "." + fracPart
+	}
+	if coef.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// StringFixed returns d rounded to places decimal places, padded with
+// trailing zeros.
+func (d Decimal) StringFixed(places int32) string {
+	s := d.Round(places).String()
+	if places <= 0 {
+		return s
+	}
+	_, frac, found := strings.Cut(s, ".")
+	if !found {
+		s += "."
+	}
+	return s + strings.Repeat("0", int(places)-len(frac))
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number. null and "" decode to 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("upbit: invalid decimal %s", data)
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	return d.UnmarshalText(data)
+}
+
+// MarshalText encodes d in plain notation.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a decimal string. An empty string decodes to 0.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,144 @@
+package upbit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"123.45", "123.45"},
+		{"-0.00000001", "-0.00000001"},
+		{"50000000", "50000000"},
+		{"0.10000000", "0.1"},
+		{"1.5e-3", "0.0015"},
+		{"1.2E+3", "1200"},
+		{".5", "0.5"},
+		{"+7", "7"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.2.3", "-", "1e", "0x10"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q): expected error", in)
+		}
+	}
+
+	// Exponents are bounded so that parsing untrusted input stays cheap.
+	for _, in := range []string{"1e10000", "1e-10000", "0.5e-9999"} {
+		if _, err := ParseDecimal(in); err != nil {
+			t.Errorf("ParseDecimal(%q): unexpected error %v", in, err)
+		}
+	}
+	for _, in := range []string{"1e10001", "1e-10001", "0.05e-9999", "1e-2000000000", "1e2147483647"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q): expected error", in)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+
+	if got := a.Add(b).String(); got != "0.3" {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+	if got := a.Sub(b).String(); got != "-0.1" {
+		t.Errorf("0.1 - 0.2 = %s, want -0.1", got)
+	}
+	if got := MustParseDecimal("50000000").Mul(MustParseDecimal("0.00012345")).String(); got != "6172.5" {
+		t.Errorf("50000000 * 0.00012345 = %s, want 6172.5", got)
+	}
+	if got := DecimalFromInt(1).DivRound(DecimalFromInt(3), 8).String(); got != "0.33333333" {
+		t.Errorf("1 / 3 = %s, want 0.33333333", got)
+	}
+	if got := DecimalFromInt(-2).DivRound(DecimalFromInt(3), 2).String(); got != "-0.67" {
+		t.Errorf("-2 / 3 = %s, want -0.67", got)
+	}
+	if !a.Add(b).Equal(MustParseDecimal("0.300")) {
+		t.Error("Expected 0.3 == 0.300")
+	}
+	if !a.LessThan(b) || b.Cmp(a) != 1 {
+		t.Error("Expected 0.1 < 0.2")
+	}
+	if !(Decimal{}).IsZero() || (Decimal{}).String() != "0" {
+		t.Error("Expected zero value to be 0")
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	d := MustParseDecimal("1.255")
+	n := MustParseDecimal("-1.255")
+
+	tests := []struct {
+		got, want string
+	}{
+		{d.Round(2).String(), "1.26"},
+		{n.Round(2).String(), "-1.26"},
+		{d.Floor(2).String(), "1.25"},
+		{n.Floor(2).String(), "-1.26"},
+		{d.Ceil(2).String(), "1.26"},
+		{n.Ceil(2).String(), "-1.25"},
+		{d.Truncate(1).String(), "1.2"},
+		{MustParseDecimal("12345").Round(-2).String(), "12300"},
+		{d.Round(5).String(), "1.255"},
+		{d.StringFixed(5), "1.25500"},
+		{DecimalFromInt(3).StringFixed(2), "3.00"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Case %d: got %s, want %s", i, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+		D Decimal `json:"d"`
+	}
+	err := json.Unmarshal([]byte(`{"a":"0.00000001","b":12345678.12345678,"c":null,"d":""}`), &v)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v.A.String() != "0.00000001" || v.B.String() != "12345678.12345678" || !v.C.IsZero() || !v.D.IsZero() {
+		t.Errorf("Unexpected values %s %s %s %s", v.A, v.B, v.C, v.D)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"a":"0.00000001","b":"12345678.12345678","c":"0","d":"0"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":"x"}`), &v); err == nil {
+		t.Error("Expected error for invalid decimal")
+	}
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	if got := DecimalFromFloat(0.1).String(); got != "0.1" {
+		t.Errorf("DecimalFromFloat(0.1) = %s, want 0.1", got)
+	}
+	if got := MustParseDecimal("0.5").Float64(); got != 0.5 {
+		t.Errorf("Float64() = %v, want 0.5", got)
+	}
+}
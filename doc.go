@@ -17,6 +17,14 @@ Create a client:
 	// For private APIs (authentication required)
 	client := upbit.NewClient("your-access-key", "your-secret-key")
 
+Options configure the HTTP client, base URL, user agent, timeout, logger,
+rate limiter, retry policy and transport middleware:
+
+	client := upbit.NewClient(accessKey, secretKey,
+		upbit.WithTimeout(10*time.Second),
+		upbit.WithUserAgent("my-bot/1.0"),
+	)
+
 # Public APIs
 
 Get market information:
@@ -39,8 +47,8 @@ Order operations:
 	order, _ := client.PlaceOrder(&upbit.PlaceOrderRequest{
 		Market:  "KRW-BTC",
 		Side:    upbit.OrderSideBid,
-		Volume:  "0.0001",
-		Price:   "50000000",
+		Volume:  upbit.MustParseDecimal("0.0001"),
+		Price:   upbit.MustParseDecimal("50000000"),
 		OrdType: upbit.OrderTypeLimit,
 	})
 
@@ -48,19 +56,34 @@ Order operations:
 	cancelled, _ := client.CancelOrder(order.UUID)
 
 	// Get orders
-	orders, _ := client.GetOrders(&upbit.GetOrdersRequest{
-		State: upbit.OrderStateWait,
+	orders, _ := client.GetOpenOrders(&upbit.OpenOrdersRequest{
+		Market: "KRW-BTC",
 	})
 
+# Context
+
+Every method has a Context variant that accepts a context.Context for
+cancellation and deadlines. The plain methods use context.Background():
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tickers, _ := client.GetTickerContext(ctx, []string{"KRW-BTC"})
+
 # Error Handling
 
-All API errors are returned as *APIError:
+Error responses are returned as *APIError, which includes the HTTP status,
+response headers and raw body. The Err* constants match with errors.Is:
+
+	_, err := client.PlaceOrder(req)
+	if errors.Is(err, upbit.ErrInvalidPrice) {
+		// adjust the price
+	} else if upbit.IsRetryable(err) {
+		// try again later
+	}
 
-	tickers, err := client.GetTicker([]string{"KRW-BTC"})
-	if err != nil {
-		if apiErr, ok := err.(*upbit.APIError); ok {
-			fmt.Printf("API Error: %s - %s\n", apiErr.Err.Name, apiErr.Err.Message)
-		}
+	var apiErr *upbit.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Printf("%d %s: %s\n", apiErr.StatusCode, apiErr.Err.Name, apiErr.Err.Message)
 	}
 */
 package upbit
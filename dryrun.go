@@ -0,0 +1,202 @@
+package upbit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDryRun is returned for requests that would change account state while
+// the client is in dry-run mode and have no simulated equivalent.
+var ErrDryRun = errors.New("upbit: request blocked in dry-run mode")
+
+// dryRun holds the orders simulated by a client in dry-run mode.
+type dryRun struct {
+	mu     sync.Mutex
+	orders map[string]*Order
+	ids    map[string]string // identifier to UUID
+}
+
+// SetDryRun enables or disables dry-run mode. In dry-run mode PlaceOrder,
+// CancelOrder, CancelOrderByIdentifier, WithdrawCoin and WithdrawKRW
+// validate their parameters against live order and withdrawal chance data
+// and return synthesized responses instead of calling mutating endpoints.
+// Simulated orders rest as waiting and never fill. Every other request
+// that is not a GET fails with ErrDryRun. Disabling dry-run mode discards
+// the simulated orders; calls already in progress complete against the
+// discarded set. SetDryRun is safe to call concurrently with requests.
+func (c *Client) SetDryRun(enabled bool) {
+	if !enabled {
+		c.dryRun.Store(nil)
+		return
+	}
+	c.dryRun.CompareAndSwap(nil, &dryRun{orders: make(map[string]*Order), ids: make(map[string]string)})
+}
+
+// blockedByDryRun reports an error if r may not be sent in dry-run mode.
+func (c *Client) blockedByDryRun(r *request) error {
+	if c.dryRun.Load() == nil || r.method == http.MethodGet || r.endpoint == "/orders/test" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", ErrDryRun, r.method, r.endpoint)
+}
+
+// dryRunTimestamp formats now the way Upbit reports creation times.
+func dryRunTimestamp() string {
+	return time.Now().In(kst).Format(time.RFC3339)
+}
+
+// simulatePlaceOrder validates req and records a waiting order for it.
+func (c *Client) simulatePlaceOrder(ctx context.Context, d *dryRun, req *PlaceOrderRequest) (*Order, error) {
+	chance, err := c.validateOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if req.Identifier != "" {
+		if _, ok := d.ids[req.Identifier]; ok {
+			return nil, invalidOrder(ErrInvalidParameter, "Identifier", "identifier %s is already in use", req.Identifier)
+		}
+	}
+
+	o := &Order{
+		UUID:            uuid.NewString(),
+		Side:            string(req.Side),
+		OrdType:         string(req.OrdType),
+		Price:           req.Price,
+		State:           string(OrderStateWait),
+		Market:          req.Market,
+		CreatedAt:       dryRunTimestamp(),
+		Volume:          req.Volume,
+		RemainingVolume: req.Volume,
+		TimeInForce:     string(req.TimeInForce),
+		SMPType:         string(req.SMPType),
+	}
+	if req.Side == OrderSideBid {
+		total := req.Price
+		if req.OrdType == OrderTypeLimit {
+			total = req.Price.Mul(req.Volume)
+		}
+		o.ReservedFee = total.Mul(chance.BidFee)
+		o.RemainingFee = o.ReservedFee
+		o.Locked = total.Add(o.ReservedFee)
+	} else {
+		o.Locked = req.Volume
+	}
+
+	d.orders[o.UUID] = o
+	if req.Identifier != "" {
+		d.ids[req.Identifier] = o.UUID
+	}
+	resp := *o
+	return &resp, nil
+}
+
+// simulateCancelOrder cancels a simulated order, or checks that a real one
+// could be cancelled, identified by uuid or identifier. Like Upbit, it
+// returns the order as it was before cancellation.
+func (c *Client) simulateCancelOrder(ctx context.Context, d *dryRun, id, identifier string) (*Order, error) {
+	d.mu.Lock()
+	if identifier != "" {
+		id = d.ids[identifier]
+	}
+	o, ok := d.orders[id]
+	if ok {
+		defer d.mu.Unlock()
+		resp := *o
+		if err := cancellable(&resp); err != nil {
+			return nil, err
+		}
+		o.State = string(OrderStateCancel)
+		o.Locked = Decimal{}
+		o.RemainingFee = Decimal{}
+		return &resp, nil
+	}
+	d.mu.Unlock()
+
+	var detail *OrderDetail
+	var err error
+	if identifier != "" {
+		detail, err = c.GetOrderByIdentifierContext(ctx, identifier)
+	} else {
+		detail, err = c.GetOrderContext(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cancellable(&detail.Order); err != nil {
+		return nil, err
+	}
+	return &detail.Order, nil
+}
+
+// cancellable returns the error Upbit reports when cancelling a closed order.
+func cancellable(o *Order) error {
+	switch OrderState(o.State) {
+	case OrderStateDone:
+		return &APIError{Err: ErrorDetail{Name: string(ErrOrderExecuted), Message: "order has already been executed"}}
+	case OrderStateCancel:
+		return &APIError{Err: ErrorDetail{Name: string(ErrOrderCancelled), Message: "order has already been cancelled"}}
+	}
+	return nil
+}
+
+// simulateWithdraw validates a withdrawal against the withdrawal chance of
+// currency and returns a waiting withdrawal record for it.
+func (c *Client) simulateWithdraw(ctx context.Context, currency, netType, transactionType string, amount Decimal) (*Withdraw, error) {
+	if amount.Sign() <= 0 {
+		return nil, invalidOrder(ErrInvalidParameter, "Amount", "amount must be positive")
+	}
+	chance, err := c.GetWithdrawChanceContext(ctx, currency, netType)
+	if err != nil {
+		return nil, err
+	}
+
+	var fee Decimal
+	if cur := chance.Currency; cur != nil {
+		if cur.WalletState != "" && !cur.WalletState.CanWithdraw() {
+			return nil, invalidOrder(ErrInvalidParameter, "Currency", "%s wallet is %s", currency, cur.WalletState)
+		}
+		fee = cur.WithdrawFee
+	}
+	if l := chance.WithdrawLimit; l != nil {
+		if !l.CanWithdraw {
+			return nil, invalidOrder(ErrInvalidParameter, "Currency", "withdrawals of %s are not allowed", currency)
+		}
+		if amount.LessThan(l.Minimum) {
+			return nil, invalidOrder(ErrInvalidParameter, "Amount", "amount %s is below the minimum %s", amount, l.Minimum)
+		}
+		if !l.Onetime.IsZero() && amount.GreaterThan(l.Onetime) {
+			return nil, invalidOrder(ErrInvalidParameter, "Amount", "amount %s exceeds the one-time limit %s", amount, l.Onetime)
+		}
+		if !l.Daily.IsZero() && amount.GreaterThan(l.RemainingDaily) {
+			return nil, invalidOrder(ErrInvalidParameter, "Amount", "amount %s exceeds the remaining daily limit %s", amount, l.RemainingDaily)
+		}
+	}
+	if acc := chance.Account; acc != nil && amount.Add(fee).GreaterThan(acc.Balance) {
+		return nil, invalidOrder(ErrInsufficientFunds, "Amount", "withdrawal requires %s %s including fees but %s is available", amount.Add(fee), currency, acc.Balance)
+	}
+
+	if transactionType == "" {
+		transactionType = "default"
+	}
+	return &Withdraw{
+		Type:            "withdraw",
+		UUID:            uuid.NewString(),
+		Currency:        currency,
+		NetType:         netType,
+		State:           WithdrawStateWaiting,
+		CreatedAt:       dryRunTimestamp(),
+		Amount:          amount,
+		Fee:             fee,
+		TransactionType: transactionType,
+	}, nil
+}
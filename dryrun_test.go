@@ -0,0 +1,135 @@
+package upbit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Unexpected mutating request %s %s", r.Method, r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/v1/orders/chance":
+			json.NewEncoder(w).Encode(testOrderChance())
+		case "/v1/order":
+			w.Write([]byte(`{"uuid":"live-uuid","state":"done"}`))
+		case "/v1/withdraws/chance":
+			w.Write([]byte(`{
+				"currency": {"code": "BTC", "withdraw_fee": "0.0005"},
+				"account": {"currency": "BTC", "balance": "0.01"},
+				"withdraw_limit": {"minimum": "0.001", "onetime": "1", "daily": "1", "remaining_daily": "1", "can_withdraw": true}
+			}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetDryRun(true)
+
+	order, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:     "KRW-BTC",
+		Side:       OrderSideBid,
+		OrdType:    OrderTypeLimit,
+		Price:      DecimalFromInt(50000000),
+		Volume:     MustParseDecimal("0.001"),
+		Identifier: "dry-1",
+		SMPType:    SMPTypeCancelMaker,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.State != "wait" || order.Locked.String() != "50025" {
+		t.Errorf("Expected a waiting order locking 50025, got %+v", order)
+	}
+	if order.SMPType != "cancel_maker" {
+		t.Errorf("Expected smp_type 'cancel_maker', got '%s'", order.SMPType)
+	}
+
+	// Validation uses the live order chance.
+	_, err = client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		OrdType: OrderTypeLimit,
+		Price:   DecimalFromInt(50000000),
+		Volume:  MustParseDecimal("0.01"),
+	})
+	var verr *OrderValidationError
+	if !errors.As(err, &verr) || verr.Code != ErrInsufficientFunds {
+		t.Errorf("Expected insufficient_funds validation error, got %v", err)
+	}
+
+	if _, err := client.CancelOrderByIdentifier("dry-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var apiErr *APIError
+	if _, err := client.CancelOrder(order.UUID); !errors.As(err, &apiErr) || apiErr.Err.Code() != ErrOrderCancelled {
+		t.Errorf("Expected order_cancelled, got %v", err)
+	}
+	if _, err := client.CancelOrder("live-uuid"); !errors.As(err, &apiErr) || apiErr.Err.Code() != ErrOrderExecuted {
+		t.Errorf("Expected order_executed for a filled live order, got %v", err)
+	}
+
+	withdraw, err := client.WithdrawCoin(&WithdrawCoinRequest{Currency: "BTC", NetType: "BTC", Amount: MustParseDecimal("0.005"), Address: "addr"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if withdraw.Fee.String() != "0.0005" || withdraw.State != "WAITING" {
+		t.Errorf("Unexpected withdrawal %+v", withdraw)
+	}
+	_, err = client.WithdrawCoin(&WithdrawCoinRequest{Currency: "BTC", NetType: "BTC", Amount: MustParseDecimal("0.0099"), Address: "addr"})
+	if !errors.As(err, &verr) || verr.Code != ErrInsufficientFunds {
+		t.Errorf("Expected the fee to exceed the balance, got %v", err)
+	}
+	_, err = client.WithdrawCoin(&WithdrawCoinRequest{Currency: "BTC", NetType: "BTC", Amount: MustParseDecimal("0.005")})
+	if !errors.As(err, &verr) || verr.Field != "Address" {
+		t.Errorf("Expected a missing address to be rejected, got %v", err)
+	}
+
+	if _, err := client.CancelOpenOrders(nil); !errors.Is(err, ErrDryRun) {
+		t.Errorf("Expected ErrDryRun, got %v", err)
+	}
+}
+
+func TestSetDryRunConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/orders/chance":
+			json.NewEncoder(w).Encode(testOrderChance())
+		case "/v1/orders":
+			w.Write([]byte(`{"uuid":"live-uuid","state":"wait"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.SetDryRun(i%2 == 0)
+		}()
+		go func() {
+			defer wg.Done()
+			client.PlaceOrder(&PlaceOrderRequest{
+				Market:  "KRW-BTC",
+				Side:    OrderSideBid,
+				OrdType: OrderTypeLimit,
+				Price:   DecimalFromInt(50000000),
+				Volume:  MustParseDecimal("0.001"),
+			})
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,160 @@
+package upbit
+
+// The string enums below decode any value Upbit sends, including values
+// added after this package was written. Use IsValid to detect them.
+
+// ChangeType represents the direction of a price change against the
+// previous close.
+type ChangeType string
+
+const (
+	ChangeRise ChangeType = "RISE" // Price went up
+	ChangeEven ChangeType = "EVEN" // Price is unchanged
+	ChangeFall ChangeType = "FALL" // Price went down
+)
+
+// IsValid reports whether c is a known change type.
+func (c ChangeType) IsValid() bool {
+	switch c {
+	case ChangeRise, ChangeEven, ChangeFall:
+		return true
+	}
+	return false
+}
+
+func (c ChangeType) String() string { return string(c) }
+
+// AskBid represents the taker side of a trade.
+type AskBid string
+
+const (
+	AskBidAsk AskBid = "ASK" // Sell
+	AskBidBid AskBid = "BID" // Buy
+)
+
+// IsValid reports whether a is a known trade side.
+func (a AskBid) IsValid() bool {
+	return a == AskBidAsk || a == AskBidBid
+}
+
+func (a AskBid) String() string { return string(a) }
+
+// WithdrawState represents the state of a withdrawal.
+type WithdrawState string
+
+const (
+	WithdrawStateWaiting    WithdrawState = "WAITING"    // Awaiting processing
+	WithdrawStateProcessing WithdrawState = "PROCESSING" // Being processed
+	WithdrawStateDone       WithdrawState = "DONE"       // Completed
+	WithdrawStateFailed     WithdrawState = "FAILED"     // Failed
+	WithdrawStateCancelled  WithdrawState = "CANCELLED"  // Cancelled
+	WithdrawStateRejected   WithdrawState = "REJECTED"   // Rejected
+)
+
+// IsValid reports whether s is a known withdrawal state.
+func (s WithdrawState) IsValid() bool {
+	switch s {
+	case WithdrawStateWaiting, WithdrawStateProcessing, WithdrawStateDone,
+		WithdrawStateFailed, WithdrawStateCancelled, WithdrawStateRejected:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether a withdrawal in state s can no longer change.
+func (s WithdrawState) IsTerminal() bool {
+	switch s {
+	case WithdrawStateDone, WithdrawStateFailed, WithdrawStateCancelled, WithdrawStateRejected:
+		return true
+	}
+	return false
+}
+
+func (s WithdrawState) String() string { return string(s) }
+
+// DepositState represents the state of a deposit.
+type DepositState string
+
+const (
+	DepositStateProcessing          DepositState = "PROCESSING"            // Being processed
+	DepositStateAccepted            DepositState = "ACCEPTED"              // Credited
+	DepositStateCancelled           DepositState = "CANCELLED"             // Cancelled
+	DepositStateRejected            DepositState = "REJECTED"              // Rejected
+	DepositStateTravelRuleSuspected DepositState = "TRAVEL_RULE_SUSPECTED" // Held for travel rule verification
+	DepositStateRefunding           DepositState = "REFUNDING"             // Being returned
+	DepositStateRefunded            DepositState = "REFUNDED"              // Returned
+)
+
+// IsValid reports whether s is a known deposit state.
+func (s DepositState) IsValid() bool {
+	switch s {
+	case DepositStateProcessing, DepositStateAccepted, DepositStateCancelled, DepositStateRejected,
+		DepositStateTravelRuleSuspected, DepositStateRefunding, DepositStateRefunded:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether a deposit in state s can no longer change.
+func (s DepositState) IsTerminal() bool {
+	switch s {
+	case DepositStateAccepted, DepositStateCancelled, DepositStateRejected, DepositStateRefunded:
+		return true
+	}
+	return false
+}
+
+func (s DepositState) String() string { return string(s) }
+
+// WalletState represents which transfers a currency's wallet accepts.
+type WalletState string
+
+const (
+	WalletStateWorking      WalletState = "working"       // Deposits and withdrawals
+	WalletStateWithdrawOnly WalletState = "withdraw_only" // Withdrawals only
+	WalletStateDepositOnly  WalletState = "deposit_only"  // Deposits only
+	WalletStatePaused       WalletState = "paused"        // Suspended
+	WalletStateUnsupported  WalletState = "unsupported"   // Not supported
+)
+
+// IsValid reports whether s is a known wallet state.
+func (s WalletState) IsValid() bool {
+	switch s {
+	case WalletStateWorking, WalletStateWithdrawOnly, WalletStateDepositOnly,
+		WalletStatePaused, WalletStateUnsupported:
+		return true
+	}
+	return false
+}
+
+// CanDeposit reports whether deposits are accepted in state s.
+func (s WalletState) CanDeposit() bool {
+	return s == WalletStateWorking || s == WalletStateDepositOnly
+}
+
+// CanWithdraw reports whether withdrawals are accepted in state s.
+func (s WalletState) CanWithdraw() bool {
+	return s == WalletStateWorking || s == WalletStateWithdrawOnly
+}
+
+func (s WalletState) String() string { return string(s) }
+
+// BlockState represents the synchronization state of a currency's node.
+type BlockState string
+
+const (
+	BlockStateNormal   BlockState = "normal"   // In sync
+	BlockStateDelayed  BlockState = "delayed"  // Behind the chain
+	BlockStateInactive BlockState = "inactive" // Not syncing
+)
+
+// IsValid reports whether s is a known block state.
+func (s BlockState) IsValid() bool {
+	switch s {
+	case BlockStateNormal, BlockStateDelayed, BlockStateInactive:
+		return true
+	}
+	return false
+}
+
+func (s BlockState) String() string { return string(s) }
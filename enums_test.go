@@ -0,0 +1,64 @@
+package upbit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumsPreserveUnknownValues(t *testing.T) {
+	var ticker Ticker
+	if err := json.Unmarshal([]byte(`{"change":"RISE"}`), &ticker); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ticker.Change != ChangeRise || !ticker.Change.IsValid() {
+		t.Errorf("Expected RISE, got %s", ticker.Change)
+	}
+
+	var status WalletStatus
+	if err := json.Unmarshal([]byte(`{"wallet_state":"maintenance","block_state":"delayed"}`), &status); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status.WalletState.String() != "maintenance" || status.WalletState.IsValid() {
+		t.Errorf("Expected the unknown wallet state to be kept and reported invalid, got %s", status.WalletState)
+	}
+	if status.BlockState != BlockStateDelayed {
+		t.Errorf("Expected delayed, got %s", status.BlockState)
+	}
+
+	if AskBid("bid").IsValid() || !AskBidBid.IsValid() {
+		t.Error("Expected trade sides to be case-sensitive")
+	}
+}
+
+func TestTransferStates(t *testing.T) {
+	tests := []struct {
+		state    WithdrawState
+		terminal bool
+	}{
+		{WithdrawStateWaiting, false},
+		{WithdrawStateProcessing, false},
+		{WithdrawStateDone, true},
+		{WithdrawStateRejected, true},
+		{"SUBMITTING", false},
+	}
+	for _, tt := range tests {
+		w := Withdraw{State: tt.state}
+		if w.IsTerminal() != tt.terminal {
+			t.Errorf("%s: expected IsTerminal %v", tt.state, tt.terminal)
+		}
+	}
+
+	if d := (Deposit{State: DepositStateTravelRuleSuspected}); d.IsTerminal() {
+		t.Error("Expected a suspected deposit not to be terminal")
+	}
+	if d := (Deposit{State: DepositStateRefunded}); !d.IsTerminal() {
+		t.Error("Expected a refunded deposit to be terminal")
+	}
+
+	if !WalletStateDepositOnly.CanDeposit() || WalletStateDepositOnly.CanWithdraw() {
+		t.Error("Unexpected deposit_only predicates")
+	}
+	if WalletStatePaused.CanDeposit() || WalletStatePaused.CanWithdraw() {
+		t.Error("Unexpected paused predicates")
+	}
+}
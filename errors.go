@@ -1,6 +1,24 @@
 package upbit
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrorCode is an error name returned by the Upbit API. The Err* constants
+// are error values, so errors.Is(err, ErrInsufficientFunds) matches an
+// *APIError or *OrderValidationError carrying that name.
+type ErrorCode string
+
+func (c ErrorCode) Error() string {
+	return "upbit: " + string(c)
+}
 
 // ErrorDetail contains the error details from the API.
 type ErrorDetail struct {
@@ -8,38 +26,143 @@ type ErrorDetail struct {
 	Message string `json:"message"`
 }
 
+// Code returns Name as an ErrorCode, for comparison with the Err* constants.
+func (d ErrorDetail) Code() ErrorCode {
+	return ErrorCode(d.Name)
+}
+
 // APIError represents an error response from the Upbit API.
 type APIError struct {
 	Err ErrorDetail `json:"error"`
+
+	StatusCode int         `json:"-"` // HTTP status code (0 for stream errors)
+	Method     string      `json:"-"` // Request method
+	Endpoint   string      `json:"-"` // Request path below the base URL, e.g. "/orders"
+	Header     http.Header `json:"-"` // Response headers
+	Body       []byte      `json:"-"` // Raw response body
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("upbit API error: %s - %s", e.Err.Name, e.Err.Message)
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("upbit API error: %s - %s", e.Err.Name, e.Err.Message)
+	}
+	return fmt.Sprintf("upbit API error (%d %s %s): %s - %s", e.StatusCode, e.Method, e.Endpoint, e.Err.Name, e.Err.Message)
+}
+
+// Is reports whether target is the ErrorCode of e.
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && e.Err.Code() == code
+}
+
+// RemainingReq returns the rate limit quota reported with the error.
+func (e *APIError) RemainingReq() (RemainingReq, bool) {
+	return ParseRemainingReq(e.Header.Get("Remaining-Req"))
+}
+
+// newAPIError builds the error for a failed response. Bodies that are not
+// Upbit error objects are kept in Body and named after the status code.
+func newAPIError(r *request, resp *response) *APIError {
+	e := &APIError{}
+	if err := json.Unmarshal(resp.body, e); err != nil || e.Err.Name == "" {
+		code := ErrUnknown
+		switch {
+		case resp.statusCode == http.StatusTooManyRequests:
+			code = ErrTooManyRequests
+		case resp.statusCode == http.StatusUnauthorized:
+			code = ErrUnauthorized
+		case resp.statusCode >= 500:
+			code = ErrServerError
+		}
+		e.Err = ErrorDetail{Name: string(code), Message: string(resp.body)}
+	}
+	e.StatusCode = resp.statusCode
+	e.Method = r.method
+	e.Endpoint = r.endpoint
+	e.Header = resp.header
+	e.Body = resp.body
+	return e
 }
 
 // Common error names returned by Upbit API
 const (
-	ErrInvalidParameter       = "invalid_parameter"
-	ErrUnauthorized           = "unauthorized"
-	ErrInvalidQuery           = "invalid_query"
-	ErrJWTVerificationFailed  = "jwt_verification_fail"
-	ErrExpiredAccessKey       = "expired_access_key"
-	ErrNonceUsed              = "nonce_used"
-	ErrNoAuthorizationIP      = "no_authorization_ip"
-	ErrOutOfScope             = "out_of_scope"
-	ErrTooManyRequests        = "too_many_requests"
-	ErrOrderNotFound          = "order_not_found"
-	ErrInsufficientFunds      = "insufficient_funds"
-	ErrUnderMinTotalBid       = "under_min_total_bid"
-	ErrUnderMinTotalAsk       = "under_min_total_ask"
-	ErrWidgetMakerOnlyOrder   = "widgetmaker_only_order"
-	ErrMarketOrderDisabled    = "market_order_disabled"
-	ErrInvalidVolume          = "invalid_volume"
-	ErrInvalidPrice           = "invalid_price"
-	ErrInvalidMarket          = "invalid_market"
-	ErrOrderCancelled         = "order_cancelled"
-	ErrOrderExecuted          = "order_executed"
-	ErrServerError            = "server_error"
-	ErrInternalServerError    = "internal_server_error"
-	ErrUnknown                = "unknown"
+	ErrInvalidParameter      ErrorCode = "invalid_parameter"
+	ErrUnauthorized          ErrorCode = "unauthorized"
+	ErrInvalidQuery          ErrorCode = "invalid_query"
+	ErrJWTVerificationFailed ErrorCode = "jwt_verification_fail"
+	ErrExpiredAccessKey      ErrorCode = "expired_access_key"
+	ErrNonceUsed             ErrorCode = "nonce_used"
+	ErrNoAuthorizationIP     ErrorCode = "no_authorization_ip"
+	ErrOutOfScope            ErrorCode = "out_of_scope"
+	ErrTooManyRequests       ErrorCode = "too_many_requests"
+	ErrOrderNotFound         ErrorCode = "order_not_found"
+	ErrInsufficientFunds     ErrorCode = "insufficient_funds"
+	ErrInsufficientFundsBid  ErrorCode = "insufficient_funds_bid"
+	ErrInsufficientFundsAsk  ErrorCode = "insufficient_funds_ask"
+	ErrUnderMinTotalBid      ErrorCode = "under_min_total_bid"
+	ErrUnderMinTotalAsk      ErrorCode = "under_min_total_ask"
+	ErrWidgetMakerOnlyOrder  ErrorCode = "widgetmaker_only_order"
+	ErrMarketOrderDisabled   ErrorCode = "market_order_disabled"
+	ErrInvalidVolume         ErrorCode = "invalid_volume"
+	ErrInvalidPrice          ErrorCode = "invalid_price"
+	ErrInvalidMarket         ErrorCode = "invalid_market"
+	ErrOrderCancelled        ErrorCode = "order_cancelled"
+	ErrOrderExecuted         ErrorCode = "order_executed"
+	ErrServerError           ErrorCode = "server_error"
+	ErrInternalServerError   ErrorCode = "internal_server_error"
+	ErrUnknown               ErrorCode = "unknown"
 )
+
+// IsRetryable reports whether err is a transient failure: a rate limit or
+// server error response, a network timeout, a reset or refused connection,
+// or a response cut short. Context cancellation and other transport errors,
+// such as DNS or TLS failures, are not retryable.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500 {
+			return true
+		}
+		switch apiErr.Err.Code() {
+		case ErrTooManyRequests, ErrServerError, ErrInternalServerError:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsAuthError reports whether err was caused by the API keys or the JWT
+// token, such as an expired key, an unregistered IP or a missing permission.
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	switch apiErr.Err.Code() {
+	case ErrUnauthorized, ErrInvalidQuery, ErrJWTVerificationFailed, ErrExpiredAccessKey,
+		ErrNonceUsed, ErrNoAuthorizationIP, ErrOutOfScope:
+		return true
+	}
+	return false
+}
+
+// IsInsufficientFunds reports whether err was returned because the
+// available balance does not cover an order or withdrawal, whether reported
+// by Upbit or by client-side validation.
+func IsInsufficientFunds(err error) bool {
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrInsufficientFundsBid) ||
+		errors.Is(err, ErrInsufficientFundsAsk)
+}
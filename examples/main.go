@@ -39,8 +39,8 @@ func main() {
 		log.Printf("Failed to get ticker: %v", err)
 	} else {
 		for _, t := range tickers {
-			fmt.Printf("%s: %.0f KRW (Change: %.2f%%)\n",
-				t.Market, t.TradePrice, t.SignedChangeRate*100)
+			fmt.Printf("%s: %s KRW (Change: %s%%)\n",
+				t.Market, t.TradePrice, t.SignedChangeRate.Mul(upbit.DecimalFromInt(100)).StringFixed(2))
 		}
 	}
 
@@ -52,12 +52,12 @@ func main() {
 	} else {
 		for _, ob := range orderbooks {
 			fmt.Printf("%s Orderbook:\n", ob.Market)
-			fmt.Printf("  Total Ask Size: %.8f\n", ob.TotalAskSize)
-			fmt.Printf("  Total Bid Size: %.8f\n", ob.TotalBidSize)
+			fmt.Printf("  Total Ask Size: %s\n", ob.TotalAskSize)
+			fmt.Printf("  Total Bid Size: %s\n", ob.TotalBidSize)
 			if len(ob.OrderbookUnits) > 0 {
 				unit := ob.OrderbookUnits[0]
-				fmt.Printf("  Best Ask: %.0f (%.8f)\n", unit.AskPrice, unit.AskSize)
-				fmt.Printf("  Best Bid: %.0f (%.8f)\n", unit.BidPrice, unit.BidSize)
+				fmt.Printf("  Best Ask: %s (%s)\n", unit.AskPrice, unit.AskSize)
+				fmt.Printf("  Best Bid: %s (%s)\n", unit.BidPrice, unit.BidSize)
 			}
 		}
 	}
@@ -70,7 +70,7 @@ func main() {
 	} else {
 		fmt.Printf("Candles %d\n", len(candles))
 		for _, c := range candles {
-			fmt.Printf("%s: Open=%.0f High=%.0f Low=%.0f Close=%.0f\n",
+			fmt.Printf("%s: Open=%s High=%s Low=%s Close=%s\n",
 				c.CandleDateTimeKst, c.OpeningPrice, c.HighPrice, c.LowPrice, c.TradePrice)
 		}
 	}
@@ -82,7 +82,7 @@ func main() {
 		log.Printf("Failed to get trades: %v", err)
 	} else {
 		for _, t := range trades {
-			fmt.Printf("%s %s: %.0f KRW x %.8f\n",
+			fmt.Printf("%s %s: %s KRW x %s\n",
 				t.TradeTimeUtc, t.AskBid, t.TradePrice, t.TradeVolume)
 		}
 	}
@@ -119,8 +119,8 @@ func main() {
 			order, err := privateClient.PlaceOrder(&upbit.PlaceOrderRequest{
 				Market:  "KRW-BTC",
 				Side:    upbit.OrderSideBid,
-				Volume:  "0.0001",
-				Price:   "50000000",
+				Volume:  upbit.MustParseDecimal("0.0001"),
+				Price:   upbit.MustParseDecimal("50000000"),
 				OrdType: upbit.OrderTypeLimit,
 			})
 			if err != nil {
@@ -132,9 +132,8 @@ func main() {
 
 		// Example 9: Get pending orders
 		fmt.Println("\n=== Pending Orders ===")
-		orders, err := privateClient.GetOrders(&upbit.GetOrdersRequest{
-			State: upbit.OrderStateWait,
-			Limit: 10,
+		orders, err := privateClient.GetOpenOrders(&upbit.OpenOrdersRequest{
+			States: []upbit.OrderState{upbit.OrderStateWait},
 		})
 		if err != nil {
 			log.Printf("Failed to get orders: %v", err)
@@ -1,17 +1,29 @@
 package upbit
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // === Account API ===
 
 // GetAccounts retrieves all account balances.
 func (c *Client) GetAccounts() ([]Account, error) {
-	body, err := c.get("/accounts", nil, true)
+	return c.GetAccountsContext(context.Background())
+}
+
+// GetAccountsContext is like GetAccounts but uses ctx for cancellation and deadlines.
+func (c *Client) GetAccountsContext(ctx context.Context) ([]Account, error) {
+	body, err := c.get(ctx, "/accounts", nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +59,19 @@ const (
 type TimeInForce string
 
 const (
-	TimeInForceIOC TimeInForce = "ioc" // Immediate or Cancel
-	TimeInForceFOK TimeInForce = "fok" // Fill or Kill
+	TimeInForceIOC      TimeInForce = "ioc"       // Immediate or Cancel
+	TimeInForceFOK      TimeInForce = "fok"       // Fill or Kill
+	TimeInForcePostOnly TimeInForce = "post_only" // Cancel instead of taking liquidity (limit orders only)
+)
+
+// SMPType represents the self-match prevention mode of an order, applied
+// when it would trade against another order of the same account.
+type SMPType string
+
+const (
+	SMPTypeCancelMaker SMPType = "cancel_maker" // Cancel the resting order
+	SMPTypeCancelTaker SMPType = "cancel_taker" // Cancel the incoming order
+	SMPTypeReduce      SMPType = "reduce"       // Reduce both orders by the matched volume
 )
 
 // OrderState represents the state of an order.
@@ -63,10 +86,15 @@ const (
 
 // GetOrderChance retrieves the order constraints for a market.
 func (c *Client) GetOrderChance(market string) (*OrderChance, error) {
+	return c.GetOrderChanceContext(context.Background(), market)
+}
+
+// GetOrderChanceContext is like GetOrderChance but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrderChanceContext(ctx context.Context, market string) (*OrderChance, error) {
 	params := url.Values{}
 	params.Set("market", market)
 
-	body, err := c.get("/orders/chance", params, true)
+	body, err := c.get(ctx, "/orders/chance", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -80,10 +108,15 @@ func (c *Client) GetOrderChance(market string) (*OrderChance, error) {
 
 // GetOrder retrieves a single order by UUID.
 func (c *Client) GetOrder(uuid string) (*OrderDetail, error) {
+	return c.GetOrderContext(context.Background(), uuid)
+}
+
+// GetOrderContext is like GetOrder but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrderContext(ctx context.Context, uuid string) (*OrderDetail, error) {
 	params := url.Values{}
 	params.Set("uuid", uuid)
 
-	body, err := c.get("/order", params, true)
+	body, err := c.get(ctx, "/order", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -97,10 +130,15 @@ func (c *Client) GetOrder(uuid string) (*OrderDetail, error) {
 
 // GetOrderByIdentifier retrieves a single order by custom identifier.
 func (c *Client) GetOrderByIdentifier(identifier string) (*OrderDetail, error) {
+	return c.GetOrderByIdentifierContext(context.Background(), identifier)
+}
+
+// GetOrderByIdentifierContext is like GetOrderByIdentifier but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrderByIdentifierContext(ctx context.Context, identifier string) (*OrderDetail, error) {
 	params := url.Values{}
 	params.Set("identifier", identifier)
 
-	body, err := c.get("/order", params, true)
+	body, err := c.get(ctx, "/order", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +163,15 @@ type GetOrdersRequest struct {
 }
 
 // GetOrders retrieves a list of orders.
+//
+// Deprecated: Upbit has deprecated the /orders endpoint. Use GetOpenOrders
+// or GetClosedOrdersRange instead.
 func (c *Client) GetOrders(req *GetOrdersRequest) ([]Order, error) {
+	return c.GetOrdersContext(context.Background(), req)
+}
+
+// GetOrdersContext is like GetOrders but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrdersContext(ctx context.Context, req *GetOrdersRequest) ([]Order, error) {
 	params := url.Values{}
 
 	if req != nil {
@@ -161,7 +207,66 @@ func (c *Client) GetOrders(req *GetOrdersRequest) ([]Order, error) {
 		}
 	}
 
-	body, err := c.get("/orders", params, true)
+	body, err := c.get(ctx, "/orders", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// OpenOrdersRequest represents the filters for GetOpenOrders.
+type OpenOrdersRequest struct {
+	Market  string       // Market code (optional)
+	States  []OrderState // OrderStateWait and/or OrderStateWatch (optional, default wait)
+	OrderBy string       // "asc" or "desc" by creation time (optional, default desc)
+}
+
+// params validates req and returns its query parameters.
+func (req *OpenOrdersRequest) params() (url.Values, error) {
+	params := url.Values{}
+	if req == nil {
+		return params, nil
+	}
+	if req.Market != "" {
+		params.Set("market", req.Market)
+	}
+	for _, state := range req.States {
+		if state != OrderStateWait && state != OrderStateWatch {
+			return nil, invalidOrder(ErrInvalidParameter, "States", "%q is not an open order state", state)
+		}
+		params.Add("states[]", string(state))
+	}
+	if req.OrderBy != "" {
+		params.Set("order_by", req.OrderBy)
+	}
+	return params, nil
+}
+
+// GetOpenOrders retrieves all open orders matching req, reading every page.
+// A nil req returns all waiting orders. Like AllOpenOrders, the result is
+// not a consistent snapshot when orders change while it is read.
+func (c *Client) GetOpenOrders(req *OpenOrdersRequest) ([]Order, error) {
+	return c.GetOpenOrdersContext(context.Background(), req)
+}
+
+// GetOpenOrdersContext is like GetOpenOrders but uses ctx for cancellation and deadlines.
+func (c *Client) GetOpenOrdersContext(ctx context.Context, req *OpenOrdersRequest) ([]Order, error) {
+	return collect(c.AllOpenOrdersContext(ctx, req))
+}
+
+// getOpenOrdersPage retrieves one page of /orders/open. params is not
+// modified, so it may be shared between pages and iterations.
+func (c *Client) getOpenOrdersPage(ctx context.Context, params url.Values, page, limit int) ([]Order, error) {
+	params = maps.Clone(params)
+	params.Set("page", strconv.Itoa(page))
+	params.Set("limit", strconv.Itoa(limit))
+
+	body, err := c.get(ctx, "/orders/open", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -173,8 +278,50 @@ func (c *Client) GetOrders(req *GetOrdersRequest) ([]Order, error) {
 	return orders, nil
 }
 
+// ClosedOrdersRequest represents the filters for GetClosedOrdersRange and
+// AllClosedOrders.
+type ClosedOrdersRequest struct {
+	Market  string       // Market code (optional)
+	States  []OrderState // OrderStateDone and/or OrderStateCancel (optional, default both)
+	Start   time.Time    // Oldest creation time (optional, default seven days before End)
+	End     time.Time    // Newest creation time (optional, default now)
+	OrderBy string       // "asc" or "desc" by creation time (optional, default desc)
+}
+
+// window validates req and returns its time range with defaults applied.
+func (req *ClosedOrdersRequest) window() (time.Time, time.Time, error) {
+	for _, state := range req.States {
+		if state != OrderStateDone && state != OrderStateCancel {
+			return time.Time{}, time.Time{}, invalidOrder(ErrInvalidParameter, "States", "%q is not a closed order state", state)
+		}
+	}
+	if req.OrderBy != "" && req.OrderBy != "asc" && req.OrderBy != "desc" {
+		return time.Time{}, time.Time{}, invalidOrder(ErrInvalidParameter, "OrderBy", "order %q is not asc or desc", req.OrderBy)
+	}
+	end := req.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := req.Start
+	if start.IsZero() {
+		start = end.Add(-closedOrdersWindow)
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, invalidOrder(ErrInvalidParameter, "Start", "start %s is after end %s", start, end)
+	}
+	return start, end, nil
+}
+
 // GetClosedOrders retrieves completed/cancelled orders.
+//
+// Deprecated: Use GetClosedOrdersRange, which takes a time.Time range and
+// reads every page and window of it.
 func (c *Client) GetClosedOrders(market string, states []OrderState, startTime, endTime string, limit int, orderBy string) ([]Order, error) {
+	return c.GetClosedOrdersContext(context.Background(), market, states, startTime, endTime, limit, orderBy)
+}
+
+// GetClosedOrdersContext is like GetClosedOrders but uses ctx for cancellation and deadlines.
+func (c *Client) GetClosedOrdersContext(ctx context.Context, market string, states []OrderState, startTime, endTime string, limit int, orderBy string) ([]Order, error) {
 	params := url.Values{}
 
 	if market != "" {
@@ -198,7 +345,7 @@ func (c *Client) GetClosedOrders(market string, states []OrderState, startTime,
 		params.Set("order_by", orderBy)
 	}
 
-	body, err := c.get("/orders/closed", params, true)
+	body, err := c.get(ctx, "/orders/closed", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -210,29 +357,90 @@ func (c *Client) GetClosedOrders(market string, states []OrderState, startTime,
 	return orders, nil
 }
 
+// GetClosedOrdersRange retrieves all completed and cancelled orders
+// matching req. Ranges longer than the seven days Upbit accepts per request
+// are split into windows and the results merged without duplicates. A nil
+// req returns the orders closed during the last seven days.
+func (c *Client) GetClosedOrdersRange(req *ClosedOrdersRequest) ([]Order, error) {
+	return c.GetClosedOrdersRangeContext(context.Background(), req)
+}
+
+// GetClosedOrdersRangeContext is like GetClosedOrdersRange but uses ctx for cancellation and deadlines.
+func (c *Client) GetClosedOrdersRangeContext(ctx context.Context, req *ClosedOrdersRequest) ([]Order, error) {
+	return collect(c.AllClosedOrdersContext(ctx, req))
+}
+
 // PlaceOrderRequest represents the request parameters for placing an order.
 type PlaceOrderRequest struct {
 	Market      string      // Market code (required)
 	Side        OrderSide   // Order side: bid or ask (required)
-	Volume      string      // Order volume (required for limit/market sell)
-	Price       string      // Order price (required for limit/market buy)
+	Volume      Decimal     // Order volume (required for limit/market sell)
+	Price       Decimal     // Order price (required for limit/market buy)
 	OrdType     OrderType   // Order type (required)
 	Identifier  string      // Custom identifier (optional)
 	TimeInForce TimeInForce // Time in force option (optional)
+	SMPType     SMPType     // Self-match prevention mode (optional)
 }
 
 // PlaceOrder places a new order.
 func (c *Client) PlaceOrder(req *PlaceOrderRequest) (*Order, error) {
+	return c.PlaceOrderContext(context.Background(), req)
+}
+
+// PlaceOrderContext is like PlaceOrder but uses ctx for cancellation and deadlines.
+// Placement is only retried when the retry policy enables RetryOrders and
+// req.Identifier is set.
+func (c *Client) PlaceOrderContext(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	if err := validateOrderOptions("", req.OrdType, req.TimeInForce, req.SMPType); err != nil {
+		return nil, err
+	}
+	if d := c.dryRun.Load(); d != nil {
+		return c.simulatePlaceOrder(ctx, d, req)
+	}
+	if c.validateOrders {
+		if _, err := c.validateOrder(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &request{
+		method:        http.MethodPost,
+		endpoint:      "/orders",
+		params:        req.params(),
+		encoding:      encodeJSON,
+		authenticated: true,
+	}
+	if req.Identifier != "" && c.retry.RetryOrders {
+		r.idempotent = true
+		r.beforeRetry = func(ctx context.Context) (*response, bool, error) {
+			return c.findOrderByIdentifier(ctx, req.Identifier)
+		}
+	}
+
+	body, err := c.send(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// params returns the request parameters of req.
+func (req *PlaceOrderRequest) params() url.Values {
 	params := url.Values{}
 	params.Set("market", req.Market)
 	params.Set("side", string(req.Side))
 	params.Set("ord_type", string(req.OrdType))
 
-	if req.Volume != "" {
-		params.Set("volume", req.Volume)
+	if !req.Volume.IsZero() {
+		params.Set("volume", req.Volume.String())
 	}
-	if req.Price != "" {
-		params.Set("price", req.Price)
+	if !req.Price.IsZero() {
+		params.Set("price", req.Price.String())
 	}
 	if req.Identifier != "" {
 		params.Set("identifier", req.Identifier)
@@ -240,8 +448,21 @@ func (c *Client) PlaceOrder(req *PlaceOrderRequest) (*Order, error) {
 	if req.TimeInForce != "" {
 		params.Set("time_in_force", string(req.TimeInForce))
 	}
+	if req.SMPType != "" {
+		params.Set("smp_type", string(req.SMPType))
+	}
+	return params
+}
 
-	body, err := c.post("/orders", params, true)
+// TestOrder submits req to Upbit's order test endpoint, which checks it like
+// PlaceOrder without placing it. The returned order is not stored.
+func (c *Client) TestOrder(req *PlaceOrderRequest) (*Order, error) {
+	return c.TestOrderContext(context.Background(), req)
+}
+
+// TestOrderContext is like TestOrder but uses ctx for cancellation and deadlines.
+func (c *Client) TestOrderContext(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	body, err := c.post(ctx, "/orders/test", req.params(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -253,12 +474,44 @@ func (c *Client) PlaceOrder(req *PlaceOrderRequest) (*Order, error) {
 	return &order, nil
 }
 
+// findOrderByIdentifier looks up an order by identifier, reporting whether
+// it exists along with the raw response body.
+func (c *Client) findOrderByIdentifier(ctx context.Context, identifier string) (*response, bool, error) {
+	params := url.Values{}
+	params.Set("identifier", identifier)
+
+	resp, err := c.roundTrip(ctx, &request{
+		method:        http.MethodGet,
+		endpoint:      "/order",
+		params:        params,
+		authenticated: true,
+		idempotent:    true,
+	})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Err.Code() == ErrOrderNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return resp, true, nil
+}
+
 // CancelOrder cancels an order by UUID.
 func (c *Client) CancelOrder(uuid string) (*Order, error) {
+	return c.CancelOrderContext(context.Background(), uuid)
+}
+
+// CancelOrderContext is like CancelOrder but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrderContext(ctx context.Context, uuid string) (*Order, error) {
+	if d := c.dryRun.Load(); d != nil {
+		return c.simulateCancelOrder(ctx, d, uuid, "")
+	}
+
 	params := url.Values{}
 	params.Set("uuid", uuid)
 
-	body, err := c.delete("/order", params, true)
+	body, err := c.delete(ctx, "/order", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -272,10 +525,19 @@ func (c *Client) CancelOrder(uuid string) (*Order, error) {
 
 // CancelOrderByIdentifier cancels an order by custom identifier.
 func (c *Client) CancelOrderByIdentifier(identifier string) (*Order, error) {
+	return c.CancelOrderByIdentifierContext(context.Background(), identifier)
+}
+
+// CancelOrderByIdentifierContext is like CancelOrderByIdentifier but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrderByIdentifierContext(ctx context.Context, identifier string) (*Order, error) {
+	if d := c.dryRun.Load(); d != nil {
+		return c.simulateCancelOrder(ctx, d, "", identifier)
+	}
+
 	params := url.Values{}
 	params.Set("identifier", identifier)
 
-	body, err := c.delete("/order", params, true)
+	body, err := c.delete(ctx, "/order", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -287,10 +549,236 @@ func (c *Client) CancelOrderByIdentifier(identifier string) (*Order, error) {
 	return &order, nil
 }
 
+// maxCancelBatch is the largest number of orders /orders/uuids accepts.
+const maxCancelBatch = 20
+
+// CancelOrders cancels orders by UUID. Lists longer than Upbit's batch size
+// are sent in several requests and their results merged; an error is only
+// returned if a request fails as a whole.
+func (c *Client) CancelOrders(uuids []string) (*CancelOrdersResult, error) {
+	return c.CancelOrdersContext(context.Background(), uuids)
+}
+
+// CancelOrdersContext is like CancelOrders but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrdersContext(ctx context.Context, uuids []string) (*CancelOrdersResult, error) {
+	return c.cancelOrderBatches(ctx, "uuids[]", uuids)
+}
+
+// CancelOrdersByIdentifier cancels orders by custom identifier, like CancelOrders.
+func (c *Client) CancelOrdersByIdentifier(identifiers []string) (*CancelOrdersResult, error) {
+	return c.CancelOrdersByIdentifierContext(context.Background(), identifiers)
+}
+
+// CancelOrdersByIdentifierContext is like CancelOrdersByIdentifier but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrdersByIdentifierContext(ctx context.Context, identifiers []string) (*CancelOrdersResult, error) {
+	return c.cancelOrderBatches(ctx, "identifiers[]", identifiers)
+}
+
+// cancelOrderBatches sends ids to /orders/uuids in batches of maxCancelBatch.
+// On error, the result holds the batches that completed.
+func (c *Client) cancelOrderBatches(ctx context.Context, key string, ids []string) (*CancelOrdersResult, error) {
+	result := &CancelOrdersResult{}
+	for batch := range slices.Chunk(ids, maxCancelBatch) {
+		params := url.Values{}
+		for _, id := range batch {
+			params.Add(key, id)
+		}
+
+		body, err := c.delete(ctx, "/orders/uuids", params, true)
+		if err != nil {
+			return result, err
+		}
+
+		var r CancelOrdersResult
+		if err := json.Unmarshal(body, &r); err != nil {
+			return result, err
+		}
+		result.merge(&r)
+	}
+	return result, nil
+}
+
+// merge appends the orders of r to res.
+func (res *CancelOrdersResult) merge(r *CancelOrdersResult) {
+	res.Success.Count += r.Success.Count
+	res.Success.Orders = append(res.Success.Orders, r.Success.Orders...)
+	res.Failed.Count += r.Failed.Count
+	res.Failed.Orders = append(res.Failed.Orders, r.Failed.Orders...)
+}
+
+// CancelOpenOrdersRequest represents the filters for cancelling open orders.
+type CancelOpenOrdersRequest struct {
+	Side            OrderSide // Only cancel this side (optional, default both)
+	Markets         []string  // Only cancel orders in these markets (optional)
+	QuoteCurrencies []string  // Only cancel orders in markets quoted in these currencies (optional)
+	ExcludedMarkets []string  // Never cancel orders in these markets (optional)
+	Count           int       // Maximum number of orders to cancel (optional, max 300)
+	OrderBy         string    // Which orders to cancel first by creation time: asc or desc (optional)
+}
+
+// CancelOpenOrders cancels open orders matching req. A nil req cancels
+// every open order. Markets and QuoteCurrencies are mutually exclusive.
+func (c *Client) CancelOpenOrders(req *CancelOpenOrdersRequest) (*CancelOrdersResult, error) {
+	return c.CancelOpenOrdersContext(context.Background(), req)
+}
+
+// CancelOpenOrdersContext is like CancelOpenOrders but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOpenOrdersContext(ctx context.Context, req *CancelOpenOrdersRequest) (*CancelOrdersResult, error) {
+	params := url.Values{}
+	params.Set("cancel_side", "all")
+
+	if req != nil {
+		if len(req.Markets) > 0 && len(req.QuoteCurrencies) > 0 {
+			return nil, invalidOrder(ErrInvalidParameter, "Markets", "Markets and QuoteCurrencies cannot be combined")
+		}
+		if req.Side != "" {
+			params.Set("cancel_side", string(req.Side))
+		}
+		if len(req.Markets) > 0 {
+			params.Set("pairs", strings.Join(req.Markets, ","))
+		}
+		if len(req.QuoteCurrencies) > 0 {
+			params.Set("quote_currencies", strings.Join(req.QuoteCurrencies, ","))
+		}
+		if len(req.ExcludedMarkets) > 0 {
+			params.Set("excluded_pairs", strings.Join(req.ExcludedMarkets, ","))
+		}
+		if req.Count > 0 {
+			params.Set("count", strconv.Itoa(req.Count))
+		}
+		if req.OrderBy != "" {
+			params.Set("order_by", req.OrderBy)
+		}
+	}
+
+	body, err := c.delete(ctx, "/orders/open", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result CancelOrdersResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReplaceOrderRequest represents the request parameters for cancelling an
+// open order and placing a replacement in one call.
+type ReplaceOrderRequest struct {
+	PrevOrderUUID       string      // UUID of the order to cancel (this or PrevOrderIdentifier)
+	PrevOrderIdentifier string      // Identifier of the order to cancel
+	NewOrdType          OrderType   // Order type of the replacement (required)
+	NewVolume           Decimal     // Volume of the replacement
+	NewPrice            Decimal     // Price of the replacement
+	NewIdentifier       string      // Custom identifier of the replacement (optional)
+	NewTimeInForce      TimeInForce // Time in force of the replacement (optional)
+	NewSMPType          SMPType     // Self-match prevention mode of the replacement (optional)
+	RemainingVolume     bool        // Reuse the cancelled order's remaining volume instead of NewVolume
+}
+
+// ReplaceOrderResult holds both sides of a replaced order.
+type ReplaceOrderResult struct {
+	Cancelled          *Order       // The cancelled order
+	New                *OrderDetail // The replacement, nil if it could not be fetched
+	NewOrderUUID       string       // UUID of the replacement
+	NewOrderIdentifier string       // Identifier of the replacement
+}
+
+// ReplaceOrder cancels an open order and places a replacement in a single
+// request, so no gap is left between the two. The market and side of the
+// replacement are those of the cancelled order.
+func (c *Client) ReplaceOrder(req *ReplaceOrderRequest) (*ReplaceOrderResult, error) {
+	return c.ReplaceOrderContext(context.Background(), req)
+}
+
+// ReplaceOrderContext is like ReplaceOrder but uses ctx for cancellation and deadlines.
+// The exchange creates the replacement asynchronously, so if it cannot be
+// found yet New holds only its UUID, market, side, type and a wait state.
+// If fetching the replacement fails otherwise, the result is returned
+// together with the error; the replacement has been placed regardless.
+func (c *Client) ReplaceOrderContext(ctx context.Context, req *ReplaceOrderRequest) (*ReplaceOrderResult, error) {
+	if (req.PrevOrderUUID == "") == (req.PrevOrderIdentifier == "") {
+		return nil, invalidOrder(ErrInvalidParameter, "PrevOrderUUID", "exactly one of PrevOrderUUID and PrevOrderIdentifier is required")
+	}
+	if req.NewOrdType == "" {
+		return nil, invalidOrder(ErrInvalidParameter, "NewOrdType", "order type is required")
+	}
+	if err := validateOrderOptions("New", req.NewOrdType, req.NewTimeInForce, req.NewSMPType); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if req.PrevOrderUUID != "" {
+		params.Set("prev_order_uuid", req.PrevOrderUUID)
+	} else {
+		params.Set("prev_order_identifier", req.PrevOrderIdentifier)
+	}
+	params.Set("new_ord_type", string(req.NewOrdType))
+
+	if req.RemainingVolume {
+		params.Set("new_volume", "remain_only")
+	} else if !req.NewVolume.IsZero() {
+		params.Set("new_volume", req.NewVolume.String())
+	}
+	if !req.NewPrice.IsZero() {
+		params.Set("new_price", req.NewPrice.String())
+	}
+	if req.NewIdentifier != "" {
+		params.Set("new_identifier", req.NewIdentifier)
+	}
+	if req.NewTimeInForce != "" {
+		params.Set("new_time_in_force", string(req.NewTimeInForce))
+	}
+	if req.NewSMPType != "" {
+		params.Set("new_smp_type", string(req.NewSMPType))
+	}
+
+	body, err := c.post(ctx, "/orders/cancel_and_new", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Order
+		NewOrderUUID       string `json:"new_order_uuid"`
+		NewOrderIdentifier string `json:"new_order_identifier"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &ReplaceOrderResult{
+		Cancelled:          &resp.Order,
+		NewOrderUUID:       resp.NewOrderUUID,
+		NewOrderIdentifier: resp.NewOrderIdentifier,
+	}
+	result.New, err = c.GetOrderContext(ctx, resp.NewOrderUUID)
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Err.Code() != ErrOrderNotFound {
+			return result, fmt.Errorf("failed to fetch replacement order: %w", err)
+		}
+		result.New = &OrderDetail{Order: Order{
+			UUID:    resp.NewOrderUUID,
+			Side:    resp.Side,
+			OrdType: string(req.NewOrdType),
+			State:   string(OrderStateWait),
+			Market:  resp.Market,
+		}}
+	}
+	return result, nil
+}
+
 // === Withdrawal API ===
 
 // GetWithdraws retrieves a list of withdrawals.
 func (c *Client) GetWithdraws(currency, state string, uuids, txids []string, limit int, page int, orderBy string) ([]Withdraw, error) {
+	return c.GetWithdrawsContext(context.Background(), currency, state, uuids, txids, limit, page, orderBy)
+}
+
+// GetWithdrawsContext is like GetWithdraws but uses ctx for cancellation and deadlines.
+func (c *Client) GetWithdrawsContext(ctx context.Context, currency, state string, uuids, txids []string, limit int, page int, orderBy string) ([]Withdraw, error) {
 	params := url.Values{}
 
 	if currency != "" {
@@ -315,7 +803,7 @@ func (c *Client) GetWithdraws(currency, state string, uuids, txids []string, lim
 		params.Set("order_by", orderBy)
 	}
 
-	body, err := c.get("/withdraws", params, true)
+	body, err := c.get(ctx, "/withdraws", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -329,10 +817,15 @@ func (c *Client) GetWithdraws(currency, state string, uuids, txids []string, lim
 
 // GetWithdraw retrieves a single withdrawal by UUID.
 func (c *Client) GetWithdraw(uuid string) (*Withdraw, error) {
+	return c.GetWithdrawContext(context.Background(), uuid)
+}
+
+// GetWithdrawContext is like GetWithdraw but uses ctx for cancellation and deadlines.
+func (c *Client) GetWithdrawContext(ctx context.Context, uuid string) (*Withdraw, error) {
 	params := url.Values{}
 	params.Set("uuid", uuid)
 
-	body, err := c.get("/withdraw", params, true)
+	body, err := c.get(ctx, "/withdraw", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -346,13 +839,18 @@ func (c *Client) GetWithdraw(uuid string) (*Withdraw, error) {
 
 // GetWithdrawChance retrieves withdrawal constraints for a currency.
 func (c *Client) GetWithdrawChance(currency, netType string) (*WithdrawChance, error) {
+	return c.GetWithdrawChanceContext(context.Background(), currency, netType)
+}
+
+// GetWithdrawChanceContext is like GetWithdrawChance but uses ctx for cancellation and deadlines.
+func (c *Client) GetWithdrawChanceContext(ctx context.Context, currency, netType string) (*WithdrawChance, error) {
 	params := url.Values{}
 	params.Set("currency", currency)
 	if netType != "" {
 		params.Set("net_type", netType)
 	}
 
-	body, err := c.get("/withdraws/chance", params, true)
+	body, err := c.get(ctx, "/withdraws/chance", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -366,20 +864,39 @@ func (c *Client) GetWithdrawChance(currency, netType string) (*WithdrawChance, e
 
 // WithdrawCoinRequest represents the request parameters for coin withdrawal.
 type WithdrawCoinRequest struct {
-	Currency         string // Currency code (required)
-	NetType          string // Network type (required)
-	Amount           string // Withdrawal amount (required)
-	Address          string // Withdrawal address (required)
-	SecondaryAddress string // Secondary address (optional, e.g., XRP tag)
-	TransactionType  string // Transaction type (optional)
+	Currency         string  // Currency code (required)
+	NetType          string  // Network type (required)
+	Amount           Decimal // Withdrawal amount (required)
+	Address          string  // Withdrawal address (required)
+	SecondaryAddress string  // Secondary address (optional, e.g., XRP tag)
+	TransactionType  string  // Transaction type (optional)
 }
 
 // WithdrawCoin withdraws cryptocurrency to an external address.
 func (c *Client) WithdrawCoin(req *WithdrawCoinRequest) (*Withdraw, error) {
+	return c.WithdrawCoinContext(context.Background(), req)
+}
+
+// WithdrawCoinContext is like WithdrawCoin but uses ctx for cancellation and deadlines.
+func (c *Client) WithdrawCoinContext(ctx context.Context, req *WithdrawCoinRequest) (*Withdraw, error) {
+	if c.dryRun.Load() != nil {
+		// Upbit rejects these as missing parameters; check them here so a
+		// dry run fails where the real withdrawal would.
+		switch {
+		case req.Currency == "":
+			return nil, invalidOrder(ErrInvalidParameter, "Currency", "currency is required")
+		case req.NetType == "":
+			return nil, invalidOrder(ErrInvalidParameter, "NetType", "net type is required")
+		case req.Address == "":
+			return nil, invalidOrder(ErrInvalidParameter, "Address", "address is required")
+		}
+		return c.simulateWithdraw(ctx, req.Currency, req.NetType, req.TransactionType, req.Amount)
+	}
+
 	params := url.Values{}
 	params.Set("currency", req.Currency)
 	params.Set("net_type", req.NetType)
-	params.Set("amount", req.Amount)
+	params.Set("amount", req.Amount.String())
 	params.Set("address", req.Address)
 
 	if req.SecondaryAddress != "" {
@@ -389,7 +906,7 @@ func (c *Client) WithdrawCoin(req *WithdrawCoinRequest) (*Withdraw, error) {
 		params.Set("transaction_type", req.TransactionType)
 	}
 
-	body, err := c.post("/withdraws/coin", params, true)
+	body, err := c.post(ctx, "/withdraws/coin", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -402,14 +919,23 @@ func (c *Client) WithdrawCoin(req *WithdrawCoinRequest) (*Withdraw, error) {
 }
 
 // WithdrawKRW withdraws KRW to a registered bank account.
-func (c *Client) WithdrawKRW(amount string, twoFactorType string) (*Withdraw, error) {
+func (c *Client) WithdrawKRW(amount Decimal, twoFactorType string) (*Withdraw, error) {
+	return c.WithdrawKRWContext(context.Background(), amount, twoFactorType)
+}
+
+// WithdrawKRWContext is like WithdrawKRW but uses ctx for cancellation and deadlines.
+func (c *Client) WithdrawKRWContext(ctx context.Context, amount Decimal, twoFactorType string) (*Withdraw, error) {
+	if c.dryRun.Load() != nil {
+		return c.simulateWithdraw(ctx, "KRW", "", "", amount)
+	}
+
 	params := url.Values{}
-	params.Set("amount", amount)
+	params.Set("amount", amount.String())
 	if twoFactorType != "" {
 		params.Set("two_factor_type", twoFactorType)
 	}
 
-	body, err := c.post("/withdraws/krw", params, true)
+	body, err := c.post(ctx, "/withdraws/krw", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -425,6 +951,11 @@ func (c *Client) WithdrawKRW(amount string, twoFactorType string) (*Withdraw, er
 
 // GetDeposits retrieves a list of deposits.
 func (c *Client) GetDeposits(currency, state string, uuids, txids []string, limit int, page int, orderBy string) ([]Deposit, error) {
+	return c.GetDepositsContext(context.Background(), currency, state, uuids, txids, limit, page, orderBy)
+}
+
+// GetDepositsContext is like GetDeposits but uses ctx for cancellation and deadlines.
+func (c *Client) GetDepositsContext(ctx context.Context, currency, state string, uuids, txids []string, limit int, page int, orderBy string) ([]Deposit, error) {
 	params := url.Values{}
 
 	if currency != "" {
@@ -449,7 +980,7 @@ func (c *Client) GetDeposits(currency, state string, uuids, txids []string, limi
 		params.Set("order_by", orderBy)
 	}
 
-	body, err := c.get("/deposits", params, true)
+	body, err := c.get(ctx, "/deposits", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -463,10 +994,15 @@ func (c *Client) GetDeposits(currency, state string, uuids, txids []string, limi
 
 // GetDeposit retrieves a single deposit by UUID.
 func (c *Client) GetDeposit(uuid string) (*Deposit, error) {
+	return c.GetDepositContext(context.Background(), uuid)
+}
+
+// GetDepositContext is like GetDeposit but uses ctx for cancellation and deadlines.
+func (c *Client) GetDepositContext(ctx context.Context, uuid string) (*Deposit, error) {
 	params := url.Values{}
 	params.Set("uuid", uuid)
 
-	body, err := c.get("/deposit", params, true)
+	body, err := c.get(ctx, "/deposit", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -480,11 +1016,16 @@ func (c *Client) GetDeposit(uuid string) (*Deposit, error) {
 
 // GenerateDepositAddress generates a new deposit address for a currency.
 func (c *Client) GenerateDepositAddress(currency, netType string) (*DepositAddress, error) {
+	return c.GenerateDepositAddressContext(context.Background(), currency, netType)
+}
+
+// GenerateDepositAddressContext is like GenerateDepositAddress but uses ctx for cancellation and deadlines.
+func (c *Client) GenerateDepositAddressContext(ctx context.Context, currency, netType string) (*DepositAddress, error) {
 	params := url.Values{}
 	params.Set("currency", currency)
 	params.Set("net_type", netType)
 
-	body, err := c.post("/deposits/generate_coin_address", params, true)
+	body, err := c.post(ctx, "/deposits/generate_coin_address", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -498,7 +1039,12 @@ func (c *Client) GenerateDepositAddress(currency, netType string) (*DepositAddre
 
 // GetDepositAddresses retrieves all deposit addresses.
 func (c *Client) GetDepositAddresses() ([]DepositAddress, error) {
-	body, err := c.get("/deposits/coin_addresses", nil, true)
+	return c.GetDepositAddressesContext(context.Background())
+}
+
+// GetDepositAddressesContext is like GetDepositAddresses but uses ctx for cancellation and deadlines.
+func (c *Client) GetDepositAddressesContext(ctx context.Context) ([]DepositAddress, error) {
+	body, err := c.get(ctx, "/deposits/coin_addresses", nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -512,11 +1058,16 @@ func (c *Client) GetDepositAddresses() ([]DepositAddress, error) {
 
 // GetDepositAddress retrieves a specific deposit address.
 func (c *Client) GetDepositAddress(currency, netType string) (*DepositAddress, error) {
+	return c.GetDepositAddressContext(context.Background(), currency, netType)
+}
+
+// GetDepositAddressContext is like GetDepositAddress but uses ctx for cancellation and deadlines.
+func (c *Client) GetDepositAddressContext(ctx context.Context, currency, netType string) (*DepositAddress, error) {
 	params := url.Values{}
 	params.Set("currency", currency)
 	params.Set("net_type", netType)
 
-	body, err := c.get("/deposits/coin_address", params, true)
+	body, err := c.get(ctx, "/deposits/coin_address", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -532,7 +1083,12 @@ func (c *Client) GetDepositAddress(currency, netType string) (*DepositAddress, e
 
 // GetWalletStatus retrieves the wallet status for all currencies.
 func (c *Client) GetWalletStatus() ([]WalletStatus, error) {
-	body, err := c.get("/status/wallet", nil, true)
+	return c.GetWalletStatusContext(context.Background())
+}
+
+// GetWalletStatusContext is like GetWalletStatus but uses ctx for cancellation and deadlines.
+func (c *Client) GetWalletStatusContext(ctx context.Context) ([]WalletStatus, error) {
+	body, err := c.get(ctx, "/status/wallet", nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -546,7 +1102,12 @@ func (c *Client) GetWalletStatus() ([]WalletStatus, error) {
 
 // GetAPIKeys retrieves API key information.
 func (c *Client) GetAPIKeys() ([]APIKey, error) {
-	body, err := c.get("/api_keys", nil, true)
+	return c.GetAPIKeysContext(context.Background())
+}
+
+// GetAPIKeysContext is like GetAPIKeys but uses ctx for cancellation and deadlines.
+func (c *Client) GetAPIKeysContext(ctx context.Context) ([]APIKey, error) {
+	body, err := c.get(ctx, "/api_keys", nil, true)
 	if err != nil {
 		return nil, err
 	}
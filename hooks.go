@@ -0,0 +1,68 @@
+package upbit
+
+import (
+	"context"
+	"time"
+)
+
+// Hook observes API calls, e.g. to record metrics or traces. A call spans
+// all of its attempts, so RequestStart and RequestEnd run once per call
+// regardless of retries. Hooks run synchronously and must be safe for
+// concurrent use. See the upbitmetrics and upbittrace packages for ready-made
+// hooks.
+type Hook interface {
+	// RequestStart is called before the first attempt. The returned context
+	// is used for the call and passed to RequestError and RequestEnd.
+	RequestStart(ctx context.Context, info RequestInfo) context.Context
+
+	// RequestError is called before RequestEnd when the call fails.
+	RequestError(ctx context.Context, info RequestInfo, err error)
+
+	// RequestEnd is called when the call completes. info.Attempt holds the
+	// number of attempts made.
+	RequestEnd(ctx context.Context, info RequestInfo, result RequestResult)
+}
+
+// RequestResult describes the outcome of an API call.
+type RequestResult struct {
+	StatusCode   int           // Status of the last response, 0 if none was received
+	Duration     time.Duration // Time from start to end, including retries
+	Remaining    RemainingReq  // Quota reported by the last response
+	HasRemaining bool          // Whether the last response reported Remaining-Req
+}
+
+// WithHooks appends hooks that observe every API call. Hooks are started
+// in order and ended in reverse order.
+func WithHooks(hooks ...Hook) Option {
+	return func(o *options) { o.hooks = append(o.hooks, hooks...) }
+}
+
+// observe runs r through the client's hooks.
+func (c *Client) observe(ctx context.Context, r *request) (*response, error) {
+	if len(c.hooks) == 0 {
+		resp, _, err := c.sendAttempts(ctx, r)
+		return resp, err
+	}
+
+	info := r.info(1)
+	for _, h := range c.hooks {
+		ctx = h.RequestStart(ctx, info)
+	}
+
+	start := time.Now()
+	resp, attempts, err := c.sendAttempts(ctx, r)
+	info.Attempt = attempts
+
+	result := RequestResult{Duration: time.Since(start)}
+	if resp != nil {
+		result.StatusCode = resp.statusCode
+		result.Remaining, result.HasRemaining = ParseRemainingReq(resp.header.Get("Remaining-Req"))
+	}
+	for i := len(c.hooks) - 1; i >= 0; i-- {
+		if err != nil {
+			c.hooks[i].RequestError(ctx, info, err)
+		}
+		c.hooks[i].RequestEnd(ctx, info, result)
+	}
+	return resp, err
+}
@@ -0,0 +1,105 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type ctxKey string
+
+// recordingHook records the hook calls it receives.
+type recordingHook struct {
+	name   string
+	mu     sync.Mutex
+	calls  *[]string
+	ends   []RequestResult
+	infos  []RequestInfo
+	errors []error
+}
+
+func (h *recordingHook) RequestStart(ctx context.Context, info RequestInfo) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, h.name+" start")
+	return context.WithValue(ctx, ctxKey(h.name), true)
+}
+
+func (h *recordingHook) RequestError(ctx context.Context, info RequestInfo, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, h.name+" error")
+	h.errors = append(h.errors, err)
+}
+
+func (h *recordingHook) RequestEnd(ctx context.Context, info RequestInfo, result RequestResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ctx.Value(ctxKey(h.name)) != true {
+		*h.calls = append(*h.calls, h.name+" lost context")
+	}
+	*h.calls = append(*h.calls, h.name+" end")
+	h.infos = append(h.infos, info)
+	h.ends = append(h.ends, result)
+}
+
+func TestHooks(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Remaining-Req", "group=market; min=599; sec=9")
+		switch r.URL.Path {
+		case "/v1/market/all":
+			if attempts++; attempts == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode([]Market{{Market: "KRW-BTC"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"name":"order_not_found","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	var calls []string
+	outer := &recordingHook{name: "outer", calls: &calls}
+	inner := &recordingHook{name: "inner", calls: &calls}
+	client := NewClient("access", "secret",
+		WithBaseURL(server.URL+"/v1"),
+		WithRetryPolicy(testRetryPolicy()),
+		WithHooks(outer, inner),
+	)
+
+	if _, err := client.GetMarkets(false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "outer start,inner start,inner end,outer end" {
+		t.Errorf("Unexpected hook calls %s", got)
+	}
+	if res := outer.ends[0]; res.StatusCode != http.StatusOK || !res.HasRemaining || res.Remaining.Sec != 9 || res.Duration <= 0 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if info := outer.infos[0]; info.Attempt != 2 || info.Endpoint != "/market/all" || info.Group != RateLimitGroupMarket {
+		t.Errorf("Unexpected info %+v", info)
+	}
+
+	calls = nil
+	_, err := client.GetOrder("missing")
+	if !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("Expected order_not_found, got %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "outer start,inner start,inner error,inner end,outer error,outer end" {
+		t.Errorf("Unexpected hook calls %s", got)
+	}
+	if len(inner.errors) != 1 || !errors.Is(inner.errors[0], ErrOrderNotFound) {
+		t.Errorf("Expected the call error, got %v", inner.errors)
+	}
+	if inner.ends[1].StatusCode != http.StatusNotFound || !inner.infos[1].Authenticated {
+		t.Errorf("Unexpected end %+v %+v", inner.infos[1], inner.ends[1])
+	}
+}
@@ -0,0 +1,178 @@
+package upbit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces sensitive values in log output.
+const redacted = "[REDACTED]"
+
+// sensitiveFields are parameter and JSON field names whose values are never
+// logged.
+var sensitiveFields = map[string]bool{
+	"address":           true,
+	"secondary_address": true,
+	"deposit_address":   true,
+	"access_key":        true,
+}
+
+// WithBodyLogging enables debug-level dumps of request headers and response
+// bodies for the given rate limit groups, with sensitive values redacted.
+// Dumps are written to the logger set with WithLogger when it is enabled
+// for slog.LevelDebug.
+func WithBodyLogging(groups ...RateLimitGroup) Option {
+	return func(o *options) {
+		if o.bodyLogging == nil {
+			o.bodyLogging = make(map[RateLimitGroup]bool)
+		}
+		for _, g := range groups {
+			o.bodyLogging[g] = true
+		}
+	}
+}
+
+// logExchange records one HTTP attempt. Completed requests are logged at
+// info level and failed ones at error level, followed by a debug-level dump
+// when body logging is enabled for the endpoint's group.
+func (c *Client) logExchange(ctx context.Context, r *request, info RequestInfo, req *http.Request, resp *response, latency time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", r.method),
+		slog.String("endpoint", r.endpoint),
+		slog.String("group", string(info.Group)),
+		slog.Int("attempt", info.Attempt),
+		slog.Bool("authenticated", r.authenticated),
+		slog.Duration("latency", latency),
+	}
+	if len(r.params) > 0 {
+		attrs = append(attrs, slog.String("params", redactParams(r.params)))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.statusCode))
+		if v := resp.header.Get("Remaining-Req"); v != "" {
+			attrs = append(attrs, slog.String("remaining_req", v))
+		}
+	}
+
+	level, msg := slog.LevelInfo, "upbit: request completed"
+	if err != nil {
+		level, msg = slog.LevelError, "upbit: request failed"
+		attrs = append(attrs, slog.String("error", c.redactError(err)))
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.String("error_name", apiErr.Err.Name))
+		}
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+
+	if !c.bodyLogging[info.Group] || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	dump := []slog.Attr{
+		slog.String("method", r.method),
+		slog.String("endpoint", r.endpoint),
+		slog.Int("attempt", info.Attempt),
+		slog.Any("request_header", redactHeader(req.Header)),
+	}
+	if resp != nil {
+		dump = append(dump,
+			slog.Any("response_header", redactHeader(resp.header)),
+			slog.String("response_body", c.redactBody(resp.body)))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "upbit: request dump", dump...)
+}
+
+// redactParams formats params like the query hash input, hiding the values
+// of sensitive fields.
+func redactParams(params url.Values) string {
+	safe := make(url.Values, len(params))
+	for k, v := range params {
+		if sensitiveFields[strings.TrimSuffix(k, "[]")] {
+			v = []string{redacted}
+		}
+		safe[k] = v
+	}
+	return canonicalQuery(safe)
+}
+
+// redactHeader returns a copy of h without credentials.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("Authorization") != "" {
+		h.Set("Authorization", redacted)
+	}
+	return h
+}
+
+// redactBody returns body with sensitive JSON fields and the access key
+// hidden. Bodies that are not JSON are only stripped of the access key.
+func (c *Client) redactBody(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return c.redactText(string(body))
+	}
+	out, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return redacted
+	}
+	return c.redactText(string(out))
+}
+
+// redactJSON hides the values of sensitive fields in a decoded JSON value.
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if sensitiveFields[k] {
+				v[k] = redacted
+			} else {
+				v[k] = redactJSON(field)
+			}
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = redactJSON(elem)
+		}
+	}
+	return v
+}
+
+// redactError formats err for logging. Transport errors embed the request
+// URL, so its query is redacted like the request params.
+func (c *Client) redactError(err error) string {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return c.redactText(err.Error())
+	}
+	msg := err.Error()
+	if u, perr := url.Parse(urlErr.URL); perr == nil && u.RawQuery != "" {
+		if params, qerr := url.ParseQuery(u.RawQuery); qerr == nil {
+			u.RawQuery = redactParams(params)
+		} else {
+			u.RawQuery = redacted
+		}
+		msg = strings.ReplaceAll(msg, urlErr.URL, u.String())
+	}
+	return c.redactText(msg)
+}
+
+// redactText hides the client's access key in s.
+func (c *Client) redactText(s string) string {
+	if c.accessKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.accessKey, redacted)
+}
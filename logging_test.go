@@ -0,0 +1,120 @@
+package upbit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Remaining-Req", "group=default; min=1799; sec=29")
+		switch r.URL.Path {
+		case "/v1/withdraws/coin":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"name":"insufficient_funds","message":"not enough BTC"}}`))
+		case "/v1/deposits/coin_address":
+			json.NewEncoder(w).Encode(DepositAddress{Currency: "BTC", DepositAddress: "bc1qsecretdeposit"})
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClient("my-access-key", "secret",
+		WithBaseURL(server.URL+"/v1"),
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithBodyLogging(RateLimitGroupDefault),
+	)
+
+	client.WithdrawCoin(&WithdrawCoinRequest{
+		Currency: "BTC",
+		NetType:  "BTC",
+		Amount:   MustParseDecimal("0.1"),
+		Address:  "bc1qsecretwithdraw",
+	})
+	if _, err := client.GetDepositAddress("BTC", "BTC"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var records []map[string]any
+	for line := range strings.Lines(logs.String()) {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 log records, got %d:\n%s", len(records), logs.String())
+	}
+
+	failed := records[0]
+	if failed["level"] != "ERROR" || failed["endpoint"] != "/withdraws/coin" || failed["status"] != float64(400) {
+		t.Errorf("Unexpected failure record %v", failed)
+	}
+	if failed["error_name"] != "insufficient_funds" || failed["remaining_req"] != "group=default; min=1799; sec=29" {
+		t.Errorf("Expected error name and Remaining-Req, got %v", failed)
+	}
+	if params, _ := failed["params"].(string); !strings.Contains(params, "address=[REDACTED]") || !strings.Contains(params, "amount=0.1") {
+		t.Errorf("Expected redacted params, got %q", params)
+	}
+
+	if records[2]["level"] != "INFO" || records[2]["endpoint"] != "/deposits/coin_address" {
+		t.Errorf("Unexpected completion record %v", records[2])
+	}
+	dump := records[3]
+	if dump["level"] != "DEBUG" || !strings.Contains(dump["response_body"].(string), `"deposit_address":"[REDACTED]"`) {
+		t.Errorf("Unexpected dump record %v", dump)
+	}
+
+	for _, secret := range []string{"bc1qsecretwithdraw", "bc1qsecretdeposit", "my-access-key", "Bearer"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("Expected %q to be redacted from logs", secret)
+		}
+	}
+}
+
+func TestBodyLoggingPerGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Ticker{{Market: "KRW-BTC"}})
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClient("", "",
+		WithBaseURL(server.URL+"/v1"),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithBodyLogging(RateLimitGroupOrder),
+	)
+	if _, err := client.GetTicker([]string{"KRW-BTC"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "request completed") || strings.Contains(logs.String(), "request dump") {
+		t.Errorf("Expected only the completion record, got %q", logs.String())
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	client := NewClient("my-access-key", "secret")
+	err := fmt.Errorf("request failed: %w", &url.Error{
+		Op:  "Get",
+		URL: "https://api.upbit.com/v1/withdraws/coin?address=bc1qsecret&currency=BTC",
+		Err: errors.New("connection reset by peer, key my-access-key"),
+	})
+	got := client.redactError(err)
+	for _, secret := range []string{"bc1qsecret", "my-access-key"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Expected %q to be redacted from %q", secret, got)
+		}
+	}
+	if !strings.Contains(got, "currency=BTC") || !strings.Contains(got, "connection reset by peer") {
+		t.Errorf("Expected the rest of the error to be kept, got %q", got)
+	}
+}
@@ -2,12 +2,12 @@ package upbit
 
 // Account represents a user's account balance information.
 type Account struct {
-	Currency            string `json:"currency"`
-	Balance             string `json:"balance"`
-	Locked              string `json:"locked"`
-	AvgBuyPrice         string `json:"avg_buy_price"`
-	AvgBuyPriceModified bool   `json:"avg_buy_price_modified"`
-	UnitCurrency        string `json:"unit_currency"`
+	Currency            string  `json:"currency"`
+	Balance             Decimal `json:"balance"`
+	Locked              Decimal `json:"locked"`
+	AvgBuyPrice         Decimal `json:"avg_buy_price"`
+	AvgBuyPriceModified bool    `json:"avg_buy_price_modified"`
+	UnitCurrency        string  `json:"unit_currency"`
 }
 
 // Market represents a trading pair.
@@ -30,50 +30,50 @@ type Market struct {
 
 // Ticker represents the current price information for a market.
 type Ticker struct {
-	Market             string  `json:"market"`
-	TradeDate          string  `json:"trade_date"`
-	TradeTime          string  `json:"trade_time"`
-	TradeDateKst       string  `json:"trade_date_kst"`
-	TradeTimeKst       string  `json:"trade_time_kst"`
-	TradeTimestamp     int64   `json:"trade_timestamp"`
-	OpeningPrice       float64 `json:"opening_price"`
-	HighPrice          float64 `json:"high_price"`
-	LowPrice           float64 `json:"low_price"`
-	TradePrice         float64 `json:"trade_price"`
-	PrevClosingPrice   float64 `json:"prev_closing_price"`
-	Change             string  `json:"change"`
-	ChangePrice        float64 `json:"change_price"`
-	ChangeRate         float64 `json:"change_rate"`
-	SignedChangePrice  float64 `json:"signed_change_price"`
-	SignedChangeRate   float64 `json:"signed_change_rate"`
-	TradeVolume        float64 `json:"trade_volume"`
-	AccTradePrice      float64 `json:"acc_trade_price"`
-	AccTradePrice24h   float64 `json:"acc_trade_price_24h"`
-	AccTradeVolume     float64 `json:"acc_trade_volume"`
-	AccTradeVolume24h  float64 `json:"acc_trade_volume_24h"`
-	Highest52WeekPrice float64 `json:"highest_52_week_price"`
-	Highest52WeekDate  string  `json:"highest_52_week_date"`
-	Lowest52WeekPrice  float64 `json:"lowest_52_week_price"`
-	Lowest52WeekDate   string  `json:"lowest_52_week_date"`
-	Timestamp          int64   `json:"timestamp"`
+	Market             string     `json:"market"`
+	TradeDate          string     `json:"trade_date"`
+	TradeTime          string     `json:"trade_time"`
+	TradeDateKst       string     `json:"trade_date_kst"`
+	TradeTimeKst       string     `json:"trade_time_kst"`
+	TradeTimestamp     int64      `json:"trade_timestamp"`
+	OpeningPrice       Decimal    `json:"opening_price"`
+	HighPrice          Decimal    `json:"high_price"`
+	LowPrice           Decimal    `json:"low_price"`
+	TradePrice         Decimal    `json:"trade_price"`
+	PrevClosingPrice   Decimal    `json:"prev_closing_price"`
+	Change             ChangeType `json:"change"`
+	ChangePrice        Decimal    `json:"change_price"`
+	ChangeRate         Decimal    `json:"change_rate"`
+	SignedChangePrice  Decimal    `json:"signed_change_price"`
+	SignedChangeRate   Decimal    `json:"signed_change_rate"`
+	TradeVolume        Decimal    `json:"trade_volume"`
+	AccTradePrice      Decimal    `json:"acc_trade_price"`
+	AccTradePrice24h   Decimal    `json:"acc_trade_price_24h"`
+	AccTradeVolume     Decimal    `json:"acc_trade_volume"`
+	AccTradeVolume24h  Decimal    `json:"acc_trade_volume_24h"`
+	Highest52WeekPrice Decimal    `json:"highest_52_week_price"`
+	Highest52WeekDate  string     `json:"highest_52_week_date"`
+	Lowest52WeekPrice  Decimal    `json:"lowest_52_week_price"`
+	Lowest52WeekDate   string     `json:"lowest_52_week_date"`
+	Timestamp          int64      `json:"timestamp"`
 }
 
 // Orderbook represents the order book for a market.
 type Orderbook struct {
 	Market         string           `json:"market"`
 	Timestamp      int64            `json:"timestamp"`
-	TotalAskSize   float64          `json:"total_ask_size"`
-	TotalBidSize   float64          `json:"total_bid_size"`
+	TotalAskSize   Decimal          `json:"total_ask_size"`
+	TotalBidSize   Decimal          `json:"total_bid_size"`
 	OrderbookUnits []OrderbookUnit  `json:"orderbook_units"`
 	Level          int              `json:"level,omitempty"`
 }
 
 // OrderbookUnit represents a single price level in the order book.
 type OrderbookUnit struct {
-	AskPrice float64 `json:"ask_price"`
-	BidPrice float64 `json:"bid_price"`
-	AskSize  float64 `json:"ask_size"`
-	BidSize  float64 `json:"bid_size"`
+	AskPrice Decimal `json:"ask_price"`
+	BidPrice Decimal `json:"bid_price"`
+	AskSize  Decimal `json:"ask_size"`
+	BidSize  Decimal `json:"bid_size"`
 }
 
 // Trade represents a recent trade.
@@ -82,11 +82,11 @@ type Trade struct {
 	TradeDateUtc     string  `json:"trade_date_utc"`
 	TradeTimeUtc     string  `json:"trade_time_utc"`
 	Timestamp        int64   `json:"timestamp"`
-	TradePrice       float64 `json:"trade_price"`
-	TradeVolume      float64 `json:"trade_volume"`
-	PrevClosingPrice float64 `json:"prev_closing_price"`
-	ChangePrice      float64 `json:"change_price"`
-	AskBid           string  `json:"ask_bid"`
+	TradePrice       Decimal `json:"trade_price"`
+	TradeVolume      Decimal `json:"trade_volume"`
+	PrevClosingPrice Decimal `json:"prev_closing_price"`
+	ChangePrice      Decimal `json:"change_price"`
+	AskBid           AskBid  `json:"ask_bid"`
 	SequentialID     int64   `json:"sequential_id"`
 }
 
@@ -95,15 +95,16 @@ type Candle struct {
 	Market               string  `json:"market"`
 	CandleDateTimeUtc    string  `json:"candle_date_time_utc"`
 	CandleDateTimeKst    string  `json:"candle_date_time_kst"`
-	OpeningPrice         float64 `json:"opening_price"`
-	HighPrice            float64 `json:"high_price"`
-	LowPrice             float64 `json:"low_price"`
-	TradePrice           float64 `json:"trade_price"`
+	OpeningPrice         Decimal `json:"opening_price"`
+	HighPrice            Decimal `json:"high_price"`
+	LowPrice             Decimal `json:"low_price"`
+	TradePrice           Decimal `json:"trade_price"`
 	Timestamp            int64   `json:"timestamp"`
-	CandleAccTradePrice  float64 `json:"candle_acc_trade_price"`
-	CandleAccTradeVolume float64 `json:"candle_acc_trade_volume"`
+	CandleAccTradePrice  Decimal `json:"candle_acc_trade_price"`
+	CandleAccTradeVolume Decimal `json:"candle_acc_trade_volume"`
 	Unit                 int     `json:"unit,omitempty"`
 	FirstDayOfPeriod     string  `json:"first_day_of_period,omitempty"`
+	Filled               bool    `json:"filled,omitempty"` // Synthesized for an interval without trades
 }
 
 // Order represents an order.
@@ -111,19 +112,22 @@ type Order struct {
 	UUID            string  `json:"uuid"`
 	Side            string  `json:"side"`
 	OrdType         string  `json:"ord_type"`
-	Price           string  `json:"price,omitempty"`
+	Price           Decimal `json:"price,omitzero"`
 	State           string  `json:"state"`
 	Market          string  `json:"market"`
 	CreatedAt       string  `json:"created_at"`
-	Volume          string  `json:"volume"`
-	RemainingVolume string  `json:"remaining_volume"`
-	ReservedFee     string  `json:"reserved_fee"`
-	RemainingFee    string  `json:"remaining_fee"`
-	PaidFee         string  `json:"paid_fee"`
-	Locked          string  `json:"locked"`
-	ExecutedVolume  string  `json:"executed_volume"`
+	Volume          Decimal `json:"volume"`
+	RemainingVolume Decimal `json:"remaining_volume"`
+	ReservedFee     Decimal `json:"reserved_fee"`
+	RemainingFee    Decimal `json:"remaining_fee"`
+	PaidFee         Decimal `json:"paid_fee"`
+	Locked          Decimal `json:"locked"`
+	ExecutedVolume  Decimal `json:"executed_volume"`
 	TradesCount     int     `json:"trades_count"`
 	TimeInForce     string  `json:"time_in_force,omitempty"`
+	SMPType         string  `json:"smp_type,omitempty"`
+	PreventedVolume Decimal `json:"prevented_volume,omitzero"` // Volume cancelled by self-match prevention
+	PreventedLocked Decimal `json:"prevented_locked,omitzero"` // Funds released by self-match prevention
 }
 
 // OrderDetail represents detailed order information including trades.
@@ -134,19 +138,38 @@ type OrderDetail struct {
 
 // OrderTrade represents a trade that is part of an order.
 type OrderTrade struct {
-	Market    string `json:"market"`
-	UUID      string `json:"uuid"`
-	Price     string `json:"price"`
-	Volume    string `json:"volume"`
-	Funds     string `json:"funds"`
-	Side      string `json:"side"`
-	CreatedAt string `json:"created_at"`
+	Market    string  `json:"market"`
+	UUID      string  `json:"uuid"`
+	Price     Decimal `json:"price"`
+	Volume    Decimal `json:"volume"`
+	Funds     Decimal `json:"funds"`
+	Side      string  `json:"side"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// CancelOrdersResult represents the outcome of a batch cancellation.
+type CancelOrdersResult struct {
+	Success CancelledOrders `json:"success"`
+	Failed  CancelledOrders `json:"failed"`
+}
+
+// CancelledOrders lists the orders on one side of a batch cancellation.
+type CancelledOrders struct {
+	Count  int              `json:"count"`
+	Orders []CancelledOrder `json:"orders"`
+}
+
+// CancelledOrder identifies an order in a batch cancellation result.
+type CancelledOrder struct {
+	UUID       string `json:"uuid"`
+	Market     string `json:"market"`
+	Identifier string `json:"identifier,omitempty"`
 }
 
 // OrderChance represents the order constraints for a market.
 type OrderChance struct {
-	BidFee     string `json:"bid_fee"`
-	AskFee     string `json:"ask_fee"`
+	BidFee     Decimal `json:"bid_fee"`
+	AskFee     Decimal `json:"ask_fee"`
 	Market     *struct {
 		ID         string   `json:"id"`
 		Name       string   `json:"name"`
@@ -156,13 +179,13 @@ type OrderChance struct {
 		OrderSides []string `json:"order_sides"`
 		Bid        *struct {
 			Currency  string `json:"currency"`
-			MinTotal  string `json:"min_total"`
+			MinTotal  Decimal `json:"min_total"`
 		} `json:"bid"`
 		Ask        *struct {
 			Currency  string `json:"currency"`
-			MinTotal  string `json:"min_total"`
+			MinTotal  Decimal `json:"min_total"`
 		} `json:"ask"`
-		MaxTotal   string   `json:"max_total"`
+		MaxTotal   Decimal  `json:"max_total"`
 		State      string   `json:"state"`
 	} `json:"market"`
 	BidAccount *Account `json:"bid_account"`
@@ -171,17 +194,22 @@ type OrderChance struct {
 
 // Withdraw represents a withdrawal record.
 type Withdraw struct {
-	Type            string `json:"type"`
-	UUID            string `json:"uuid"`
-	Currency        string `json:"currency"`
-	NetType         string `json:"net_type,omitempty"`
-	TxID            string `json:"txid,omitempty"`
-	State           string `json:"state"`
-	CreatedAt       string `json:"created_at"`
-	DoneAt          string `json:"done_at,omitempty"`
-	Amount          string `json:"amount"`
-	Fee             string `json:"fee"`
-	TransactionType string `json:"transaction_type"`
+	Type            string        `json:"type"`
+	UUID            string        `json:"uuid"`
+	Currency        string        `json:"currency"`
+	NetType         string        `json:"net_type,omitempty"`
+	TxID            string        `json:"txid,omitempty"`
+	State           WithdrawState `json:"state"`
+	CreatedAt       string        `json:"created_at"`
+	DoneAt          string        `json:"done_at,omitempty"`
+	Amount          Decimal       `json:"amount"`
+	Fee             Decimal       `json:"fee"`
+	TransactionType string        `json:"transaction_type"`
+}
+
+// IsTerminal reports whether the withdrawal has reached a final state.
+func (w *Withdraw) IsTerminal() bool {
+	return w.State.IsTerminal()
 }
 
 // WithdrawChance represents withdrawal constraints.
@@ -197,45 +225,50 @@ type WithdrawChance struct {
 		WalletLocked         bool `json:"wallet_locked"`
 	} `json:"member_level"`
 	Currency *struct {
-		Code          string   `json:"code"`
-		WithdrawFee   string   `json:"withdraw_fee"`
-		IsCoin        bool     `json:"is_coin"`
-		WalletState   string   `json:"wallet_state"`
-		WalletSupport []string `json:"wallet_support"`
+		Code          string      `json:"code"`
+		WithdrawFee   Decimal     `json:"withdraw_fee"`
+		IsCoin        bool        `json:"is_coin"`
+		WalletState   WalletState `json:"wallet_state"`
+		WalletSupport []string    `json:"wallet_support"`
 	} `json:"currency"`
 	Account *struct {
-		Currency            string `json:"currency"`
-		Balance             string `json:"balance"`
-		Locked              string `json:"locked"`
-		AvgBuyPrice         string `json:"avg_buy_price"`
-		AvgBuyPriceModified bool   `json:"avg_buy_price_modified"`
-		UnitCurrency        string `json:"unit_currency"`
+		Currency            string  `json:"currency"`
+		Balance             Decimal `json:"balance"`
+		Locked              Decimal `json:"locked"`
+		AvgBuyPrice         Decimal `json:"avg_buy_price"`
+		AvgBuyPriceModified bool    `json:"avg_buy_price_modified"`
+		UnitCurrency        string  `json:"unit_currency"`
 	} `json:"account"`
 	WithdrawLimit *struct {
-		Currency                   string `json:"currency"`
-		Minimum                    string `json:"minimum"`
-		Onetime                    string `json:"onetime"`
-		Daily                      string `json:"daily"`
-		RemainingDaily             string `json:"remaining_daily"`
-		RemainingDailyKrw          string `json:"remaining_daily_krw"`
-		Fixed                      int    `json:"fixed"`
-		CanWithdraw                bool   `json:"can_withdraw"`
+		Currency                   string  `json:"currency"`
+		Minimum                    Decimal `json:"minimum"`
+		Onetime                    Decimal `json:"onetime"`
+		Daily                      Decimal `json:"daily"`
+		RemainingDaily             Decimal `json:"remaining_daily"`
+		RemainingDailyKrw          Decimal `json:"remaining_daily_krw"`
+		Fixed                      int     `json:"fixed"`
+		CanWithdraw                bool    `json:"can_withdraw"`
 	} `json:"withdraw_limit"`
 }
 
 // Deposit represents a deposit record.
 type Deposit struct {
-	Type            string `json:"type"`
-	UUID            string `json:"uuid"`
-	Currency        string `json:"currency"`
-	NetType         string `json:"net_type,omitempty"`
-	TxID            string `json:"txid,omitempty"`
-	State           string `json:"state"`
-	CreatedAt       string `json:"created_at"`
-	DoneAt          string `json:"done_at,omitempty"`
-	Amount          string `json:"amount"`
-	Fee             string `json:"fee"`
-	TransactionType string `json:"transaction_type"`
+	Type            string       `json:"type"`
+	UUID            string       `json:"uuid"`
+	Currency        string       `json:"currency"`
+	NetType         string       `json:"net_type,omitempty"`
+	TxID            string       `json:"txid,omitempty"`
+	State           DepositState `json:"state"`
+	CreatedAt       string       `json:"created_at"`
+	DoneAt          string       `json:"done_at,omitempty"`
+	Amount          Decimal      `json:"amount"`
+	Fee             Decimal      `json:"fee"`
+	TransactionType string       `json:"transaction_type"`
+}
+
+// IsTerminal reports whether the deposit has reached a final state.
+func (d *Deposit) IsTerminal() bool {
+	return d.State.IsTerminal()
 }
 
 // DepositAddress represents a deposit address.
@@ -263,10 +296,10 @@ type APIKey struct {
 
 // WalletStatus represents the wallet status for a currency.
 type WalletStatus struct {
-	Currency     string `json:"currency"`
-	WalletState  string `json:"wallet_state"`
-	BlockState   string `json:"block_state"`
-	BlockHeight  int64  `json:"block_height"`
-	BlockUpdated string `json:"block_updated_at"`
-	NetType      string `json:"net_type"`
+	Currency     string      `json:"currency"`
+	WalletState  WalletState `json:"wallet_state"`
+	BlockState   BlockState  `json:"block_state"`
+	BlockHeight  int64       `json:"block_height"`
+	BlockUpdated string      `json:"block_updated_at"`
+	NetType      string      `json:"net_type"`
 }
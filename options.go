@@ -0,0 +1,115 @@
+package upbit
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout is the HTTP timeout of clients created without WithHTTPClient
+// or WithTimeout.
+const defaultTimeout = 30 * time.Second
+
+// Option configures a Client created by NewClient.
+type Option func(*options)
+
+// options collects the settings applied by NewClient.
+type options struct {
+	httpClient  *http.Client
+	timeout     time.Duration
+	region      Region
+	baseURL     string
+	wsURL       string
+	userAgent   string
+	logger      *slog.Logger
+	bodyLogging map[RateLimitGroup]bool
+	limiter     *RateLimiter
+	retry       RetryPolicy
+	middleware  []Middleware
+	hooks       []Hook
+}
+
+// WithHTTPClient sets the HTTP client used for REST requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.httpClient = client }
+}
+
+// WithRegion selects the Upbit exchange to connect to. It sets the REST and
+// WebSocket URLs unless WithBaseURL or WithWebSocketURL is also given, and
+// restricts order validation to the region's quote currencies. The default
+// is RegionKR. Every request of a client created with an unknown region
+// fails; use ParseRegion to check region codes from configuration.
+func WithRegion(region Region) Option {
+	return func(o *options) { o.region = region }
+}
+
+// WithBaseURL sets the REST API base URL, e.g. a test server's URL.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) { o.baseURL = baseURL }
+}
+
+// WithWebSocketURL sets the WebSocket base URL.
+func WithWebSocketURL(wsURL string) Option {
+	return func(o *options) { o.wsURL = wsURL }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) { o.userAgent = userAgent }
+}
+
+// WithTimeout sets the overall timeout of each HTTP request, including when
+// combined with WithHTTPClient. The supplied client is copied, not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithLogger sets the logger that records every request with its
+// parameters, status, latency and Remaining-Req header, as well as retries.
+// Credentials and withdrawal addresses are redacted. By default nothing is
+// logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithRateLimiter sets the client-side rate limiter. Passing nil disables
+// client-side throttling.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) { o.limiter = limiter }
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) { o.retry = policy }
+}
+
+// WithMiddleware appends middleware to the client's transport chain. The
+// first middleware given is the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *options) { o.middleware = append(o.middleware, middleware...) }
+}
+
+// RequestInfo describes the API call an HTTP request belongs to.
+type RequestInfo struct {
+	Method        string         // HTTP method
+	Endpoint      string         // Path below the base URL, e.g. "/orders"
+	Authenticated bool           // Whether the request carries a JWT
+	Group         RateLimitGroup // Rate limit group of the endpoint
+	Attempt       int            // 1 for the first attempt, incremented on retries
+}
+
+// RoundTripFunc performs a single HTTP exchange for an API call.
+type RoundTripFunc func(req *http.Request, info RequestInfo) (*http.Response, error)
+
+// Middleware wraps the transport of a client. It sees every attempt after
+// authentication and rate limiting, and may modify the request, inspect
+// the response or return its own.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// chain wraps rt in middleware, the first being outermost.
+func chain(rt RoundTripFunc, middleware []Middleware) RoundTripFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,110 @@
+package upbit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "my-bot/1.0" {
+			t.Errorf("Expected User-Agent 'my-bot/1.0', got '%s'", ua)
+		}
+		json.NewEncoder(w).Encode([]Ticker{{Market: "KRW-BTC"}})
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	client := NewClient("", "",
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithBaseURL(server.URL+"/v1"),
+		WithUserAgent("my-bot/1.0"),
+		WithRateLimiter(nil),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.httpClient.Timeout)
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Error("Expected the supplied HTTP client to be left unmodified")
+	}
+	if client.limiter != nil || client.retry.MaxAttempts != 1 {
+		t.Error("Expected rate limiter and retry policy options to apply")
+	}
+	if _, err := client.GetTicker([]string{"KRW-BTC"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client := NewClient("", ""); client.httpClient.Timeout != defaultTimeout || client.baseURL != BaseURL {
+		t.Error("Expected defaults without options")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("Expected header set by middleware, got '%s'", r.Header.Get("X-Trace"))
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]Account{{Currency: "KRW"}})
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var order []string
+	var infos []RequestInfo
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request, info RequestInfo) (*http.Response, error) {
+				mu.Lock()
+				order = append(order, name)
+				if name == "outer" {
+					infos = append(infos, info)
+				}
+				mu.Unlock()
+				req.Header.Set("X-Trace", "abc")
+				return next(req, info)
+			}
+		}
+	}
+
+	var logs bytes.Buffer
+	client := NewClient("access", "secret",
+		WithBaseURL(server.URL+"/v1"),
+		WithRetryPolicy(testRetryPolicy()),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithMiddleware(record("outer"), record("inner")),
+	)
+
+	if _, err := client.GetAccounts(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "outer,inner,outer,inner" {
+		t.Errorf("Unexpected middleware order %v", order)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(infos))
+	}
+	want := RequestInfo{Method: http.MethodGet, Endpoint: "/accounts", Authenticated: true, Group: RateLimitGroupDefault, Attempt: 2}
+	if infos[1] != want {
+		t.Errorf("Expected %+v, got %+v", want, infos[1])
+	}
+	if !strings.Contains(logs.String(), "retrying request") {
+		t.Errorf("Expected retry to be logged, got %q", logs.String())
+	}
+}
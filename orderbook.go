@@ -0,0 +1,193 @@
+package upbit
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// orderBookChangeBuffer is the capacity of a change notification channel.
+const orderBookChangeBuffer = 64
+
+// OrderBookChange notifies that a market's book was replaced.
+type OrderBookChange struct {
+	Market     string  // Market code
+	Timestamp  int64   // Timestamp of the new book in milliseconds
+	BestBid    Decimal // Best bid price after the change
+	BestAsk    Decimal // Best ask price after the change
+	TopChanged bool    // Whether the best bid or ask price changed
+}
+
+// OrderBookManager maintains an in-memory orderbook per market from stream
+// messages or REST snapshots. It is safe for concurrent use.
+type OrderBookManager struct {
+	mu        sync.RWMutex
+	books     map[string]Orderbook
+	listeners map[chan OrderBookChange]struct{}
+}
+
+// NewOrderBookManager creates an empty orderbook manager.
+func NewOrderBookManager() *OrderBookManager {
+	return &OrderBookManager{
+		books:     make(map[string]Orderbook),
+		listeners: make(map[chan OrderBookChange]struct{}),
+	}
+}
+
+// Run applies orderbook events from events until the channel is closed or
+// ctx is done. Other event types are ignored.
+func (m *OrderBookManager) Run(ctx context.Context, events <-chan Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			m.Apply(ev)
+		}
+	}
+}
+
+// Apply updates the book from a stream event and reports whether the event
+// was an orderbook message.
+func (m *OrderBookManager) Apply(ev Event) bool {
+	ob, ok := ev.(*OrderbookEvent)
+	if !ok {
+		return false
+	}
+	m.Set(ob.Orderbook)
+	return true
+}
+
+// Set replaces the book of book.Market, e.g. with a snapshot from
+// GetOrderbook. Books older than the one held are ignored.
+func (m *OrderBookManager) Set(book Orderbook) {
+	book.OrderbookUnits = slices.Clone(book.OrderbookUnits)
+
+	m.mu.Lock()
+	prev, had := m.books[book.Market]
+	if had && book.Timestamp < prev.Timestamp {
+		m.mu.Unlock()
+		return
+	}
+	m.books[book.Market] = book
+
+	change := OrderBookChange{Market: book.Market, Timestamp: book.Timestamp}
+	if len(book.OrderbookUnits) > 0 {
+		change.BestBid = book.OrderbookUnits[0].BidPrice
+		change.BestAsk = book.OrderbookUnits[0].AskPrice
+	}
+	change.TopChanged = !had || len(prev.OrderbookUnits) == 0 ||
+		!prev.OrderbookUnits[0].BidPrice.Equal(change.BestBid) ||
+		!prev.OrderbookUnits[0].AskPrice.Equal(change.BestAsk)
+
+	for ch := range m.listeners {
+		select {
+		case ch <- change:
+		default:
+			// The listener is behind; it can query the latest state.
+		}
+	}
+	m.mu.Unlock()
+}
+
+// Subscribe returns a channel receiving a notification for every book
+// change, and a function that stops notifications and closes the channel.
+// Notifications are dropped while the channel is full.
+func (m *OrderBookManager) Subscribe() (<-chan OrderBookChange, func()) {
+	ch := make(chan OrderBookChange, orderBookChangeBuffer)
+
+	m.mu.Lock()
+	m.listeners[ch] = struct{}{}
+	m.mu.Unlock()
+
+	cancel := sync.OnceFunc(func() {
+		m.mu.Lock()
+		delete(m.listeners, ch)
+		m.mu.Unlock()
+		close(ch)
+	})
+	return ch, cancel
+}
+
+// Markets returns the markets with a known book.
+func (m *OrderBookManager) Markets() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	markets := make([]string, 0, len(m.books))
+	for market := range m.books {
+		markets = append(markets, market)
+	}
+	slices.Sort(markets)
+	return markets
+}
+
+// Book returns a copy of the current book of a market.
+func (m *OrderBookManager) Book(market string) (Orderbook, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[market]
+	book.OrderbookUnits = slices.Clone(book.OrderbookUnits)
+	return book, ok
+}
+
+// top returns the best price level of a market.
+func (m *OrderBookManager) top(market string) (OrderbookUnit, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[market]
+	if !ok || len(book.OrderbookUnits) == 0 {
+		return OrderbookUnit{}, false
+	}
+	return book.OrderbookUnits[0], true
+}
+
+// BestBid returns the highest bid price and its size.
+func (m *OrderBookManager) BestBid(market string) (price, size Decimal, ok bool) {
+	unit, ok := m.top(market)
+	return unit.BidPrice, unit.BidSize, ok
+}
+
+// BestAsk returns the lowest ask price and its size.
+func (m *OrderBookManager) BestAsk(market string) (price, size Decimal, ok bool) {
+	unit, ok := m.top(market)
+	return unit.AskPrice, unit.AskSize, ok
+}
+
+// Spread returns the difference between the best ask and best bid.
+func (m *OrderBookManager) Spread(market string) (Decimal, bool) {
+	unit, ok := m.top(market)
+	return unit.AskPrice.Sub(unit.BidPrice), ok
+}
+
+// MidPrice returns the average of the best ask and best bid.
+func (m *OrderBookManager) MidPrice(market string) (Decimal, bool) {
+	unit, ok := m.top(market)
+	return unit.AskPrice.Add(unit.BidPrice).Div(DecimalFromInt(2)), ok
+}
+
+// Depth returns the cumulative bid and ask sizes of the best levels price
+// levels. A non-positive levels sums the whole book.
+func (m *OrderBookManager) Depth(market string, levels int) (bidSize, askSize Decimal, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[market]
+	if !ok {
+		return Decimal{}, Decimal{}, false
+	}
+	units := book.OrderbookUnits
+	if levels > 0 && levels < len(units) {
+		units = units[:levels]
+	}
+	for _, unit := range units {
+		bidSize = bidSize.Add(unit.BidSize)
+		askSize = askSize.Add(unit.AskSize)
+	}
+	return bidSize, askSize, true
+}
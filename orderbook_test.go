@@ -0,0 +1,100 @@
+package upbit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testOrderbookEvent(timestamp, bid, ask int64) *OrderbookEvent {
+	return &OrderbookEvent{
+		Orderbook: Orderbook{
+			Market:    "KRW-BTC",
+			Timestamp: timestamp,
+			OrderbookUnits: []OrderbookUnit{
+				{
+					AskPrice: DecimalFromInt(ask),
+					BidPrice: DecimalFromInt(bid),
+					AskSize:  DecimalFromInt(1),
+					BidSize:  DecimalFromInt(2),
+				},
+				{
+					AskPrice: DecimalFromInt(ask + 1000),
+					BidPrice: DecimalFromInt(bid - 1000),
+					AskSize:  DecimalFromInt(3),
+					BidSize:  MustParseDecimal("4.5"),
+				},
+			},
+		},
+		Code: "KRW-BTC",
+	}
+}
+
+func TestOrderBookManagerQueries(t *testing.T) {
+	m := NewOrderBookManager()
+	if !m.Apply(testOrderbookEvent(1, 50000000, 50010000)) {
+		t.Fatal("Expected orderbook event to be applied")
+	}
+	if m.Apply(&TickerEvent{}) {
+		t.Error("Expected ticker event to be ignored")
+	}
+
+	if price, size, ok := m.BestBid("KRW-BTC"); !ok || price.String() != "50000000" || size.String() != "2" {
+		t.Errorf("Unexpected best bid %v %v %v", price, size, ok)
+	}
+	if price, size, ok := m.BestAsk("KRW-BTC"); !ok || price.String() != "50010000" || size.String() != "1" {
+		t.Errorf("Unexpected best ask %v %v %v", price, size, ok)
+	}
+	if spread, _ := m.Spread("KRW-BTC"); spread.String() != "10000" {
+		t.Errorf("Expected spread 10000, got %v", spread)
+	}
+	if mid, _ := m.MidPrice("KRW-BTC"); mid.String() != "50005000" {
+		t.Errorf("Expected mid price 50005000, got %v", mid)
+	}
+	if bid, ask, _ := m.Depth("KRW-BTC", 0); bid.String() != "6.5" || ask.String() != "4" {
+		t.Errorf("Expected depth 6.5/4, got %v/%v", bid, ask)
+	}
+	if bid, ask, _ := m.Depth("KRW-BTC", 1); bid.String() != "2" || ask.String() != "1" {
+		t.Errorf("Expected depth 2/1, got %v/%v", bid, ask)
+	}
+	if _, _, ok := m.BestBid("KRW-ETH"); ok {
+		t.Error("Expected unknown market to report ok=false")
+	}
+
+	// Stale snapshots are ignored.
+	m.Apply(testOrderbookEvent(0, 1, 2))
+	if price, _, _ := m.BestBid("KRW-BTC"); price.String() != "50000000" {
+		t.Errorf("Expected stale book to be ignored, got best bid %v", price)
+	}
+}
+
+func TestOrderBookManagerNotifications(t *testing.T) {
+	m := NewOrderBookManager()
+	changes, cancel := m.Subscribe()
+	defer cancel()
+
+	events := make(chan Event, 2)
+	events <- testOrderbookEvent(1, 50000000, 50010000)
+	events <- testOrderbookEvent(2, 50000000, 50010000)
+	close(events)
+
+	if err := m.Run(context.Background(), events); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, wantTop := range []bool{true, false} {
+		select {
+		case change := <-changes:
+			if change.Market != "KRW-BTC" || change.TopChanged != wantTop {
+				t.Errorf("Change %d: unexpected %+v", i, change)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Change %d: timed out", i)
+		}
+	}
+
+	cancel()
+	if _, ok := <-changes; ok {
+		t.Error("Expected channel to be closed after cancel")
+	}
+}
@@ -0,0 +1,272 @@
+package upbit
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxPageLimit is the largest page size of the order, withdrawal and
+	// deposit list endpoints.
+	maxPageLimit = 100
+
+	// maxClosedOrdersLimit is the largest page size of /orders/closed.
+	maxClosedOrdersLimit = 1000
+
+	// closedOrdersWindow is the longest time range /orders/closed accepts.
+	closedOrdersWindow = 7 * 24 * time.Hour
+)
+
+// pages yields the items of consecutive pages, starting at page first, until
+// fetch returns a page shorter than limit.
+func pages[T any](first, limit int, fetch func(page, limit int) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := first; ; page++ {
+			items, err := fetch(page, limit)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < limit {
+				return
+			}
+		}
+	}
+}
+
+// AllTrades iterates over the trades of a market, newest first, following
+// the cursor until the day's history is exhausted. Break out of the loop to
+// stop early.
+func (c *Client) AllTrades(market string, daysAgo int) iter.Seq2[Trade, error] {
+	return c.AllTradesContext(context.Background(), market, daysAgo)
+}
+
+// AllTradesContext is like AllTrades but uses ctx for cancellation and deadlines.
+func (c *Client) AllTradesContext(ctx context.Context, market string, daysAgo int) iter.Seq2[Trade, error] {
+	return func(yield func(Trade, error) bool) {
+		cursor := ""
+		for {
+			page, err := c.GetTradesContext(ctx, market, "", maxTradesCount, cursor, daysAgo)
+			if err != nil {
+				yield(Trade{}, err)
+				return
+			}
+			for _, t := range page {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if len(page) < maxTradesCount {
+				return
+			}
+			cursor = strconv.FormatInt(page[len(page)-1].SequentialID, 10)
+		}
+	}
+}
+
+// AllOrders iterates over the orders matching req page by page, starting at
+// req.Page. req.Limit sets the page size and defaults to the maximum.
+//
+// Deprecated: Use AllOpenOrders or AllClosedOrders instead.
+func (c *Client) AllOrders(req *GetOrdersRequest) iter.Seq2[Order, error] {
+	return c.AllOrdersContext(context.Background(), req)
+}
+
+// AllOrdersContext is like AllOrders but uses ctx for cancellation and deadlines.
+func (c *Client) AllOrdersContext(ctx context.Context, req *GetOrdersRequest) iter.Seq2[Order, error] {
+	var r GetOrdersRequest
+	if req != nil {
+		r = *req
+	}
+	first, limit := max(r.Page, 1), r.Limit
+	if limit <= 0 || limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return pages(first, limit, func(page, limit int) ([]Order, error) {
+		r.Page, r.Limit = page, limit
+		return c.GetOrdersContext(ctx, &r)
+	})
+}
+
+// AllOpenOrders iterates over the open orders matching req page by page.
+// The pages are not a consistent snapshot: an order that fills or is
+// cancelled while paging moves later orders onto pages already read, and
+// those orders are missed. Orders placed while paging move others onto
+// later pages; such repeats are yielded once.
+func (c *Client) AllOpenOrders(req *OpenOrdersRequest) iter.Seq2[Order, error] {
+	return c.AllOpenOrdersContext(context.Background(), req)
+}
+
+// AllOpenOrdersContext is like AllOpenOrders but uses ctx for cancellation and deadlines.
+func (c *Client) AllOpenOrdersContext(ctx context.Context, req *OpenOrdersRequest) iter.Seq2[Order, error] {
+	params, err := req.params()
+	if err != nil {
+		return fail[Order](err)
+	}
+	return uniqueOrders(pages(1, maxPageLimit, func(page, limit int) ([]Order, error) {
+		return c.getOpenOrdersPage(ctx, params, page, limit)
+	}))
+}
+
+// AllClosedOrders iterates over the closed orders matching req, newest first
+// unless req.OrderBy is "asc". The range is queried in windows of at most
+// seven days and orders returned by more than one window are yielded once.
+func (c *Client) AllClosedOrders(req *ClosedOrdersRequest) iter.Seq2[Order, error] {
+	return c.AllClosedOrdersContext(context.Background(), req)
+}
+
+// AllClosedOrdersContext is like AllClosedOrders but uses ctx for cancellation and deadlines.
+func (c *Client) AllClosedOrdersContext(ctx context.Context, req *ClosedOrdersRequest) iter.Seq2[Order, error] {
+	var r ClosedOrdersRequest
+	if req != nil {
+		r = *req
+	}
+	start, end, err := r.window()
+	if err != nil {
+		return fail[Order](err)
+	}
+	asc := r.OrderBy == "asc"
+	orderBy := "desc"
+	if asc {
+		orderBy = "asc"
+	}
+
+	return uniqueOrders(func(yield func(Order, error) bool) {
+		// [from, to] is the current window. Descending iteration moves it
+		// towards start and ascending iteration towards end.
+		from, to := end.Add(-closedOrdersWindow), end
+		if asc {
+			from, to = start, start.Add(closedOrdersWindow)
+		}
+		for {
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(end) {
+				to = end
+			}
+			orders, err := c.GetClosedOrdersContext(ctx, r.Market, r.States, from.Format(time.RFC3339), to.Format(time.RFC3339), maxClosedOrdersLimit, orderBy)
+			if err != nil {
+				yield(Order{}, err)
+				return
+			}
+
+			last := to
+			if asc {
+				last = from
+			}
+			for _, o := range orders {
+				if !yield(o, nil) {
+					return
+				}
+				if created, err := time.Parse(time.RFC3339, o.CreatedAt); err == nil {
+					last = created
+				}
+			}
+
+			// A full page may leave more orders in the window, so the
+			// window is paged until a page comes back short. The next page
+			// starts at the last order seen, which it includes again. If
+			// that does not advance, more orders share that second than
+			// fit in a page and the rest cannot be reached.
+			if len(orders) == maxClosedOrdersLimit {
+				switch {
+				case asc && last.After(from):
+					from = last
+				case !asc && last.Before(to):
+					to = last
+				default:
+					yield(Order{}, fmt.Errorf("upbit: more than %d closed orders created at %s; narrow the request by market or states",
+						maxClosedOrdersLimit, last.Format(time.RFC3339)))
+					return
+				}
+				continue
+			}
+
+			if asc {
+				if !to.Before(end) {
+					return
+				}
+				from, to = to, to.Add(closedOrdersWindow)
+			} else {
+				if !from.After(start) {
+					return
+				}
+				from, to = from.Add(-closedOrdersWindow), from
+			}
+		}
+	})
+}
+
+// uniqueOrders drops orders whose UUID seq has already yielded.
+func uniqueOrders(seq iter.Seq2[Order, error]) iter.Seq2[Order, error] {
+	return func(yield func(Order, error) bool) {
+		seen := make(map[string]bool)
+		for o, err := range seq {
+			if err == nil {
+				if seen[o.UUID] {
+					continue
+				}
+				seen[o.UUID] = true
+			}
+			if !yield(o, err) {
+				return
+			}
+		}
+	}
+}
+
+// fail returns an iterator yielding only err.
+func fail[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// collect gathers the items of seq, stopping at the first error.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AllWithdraws iterates over withdrawals, newest first, page by page.
+// Empty currency or state match all withdrawals.
+func (c *Client) AllWithdraws(currency string, state WithdrawState) iter.Seq2[Withdraw, error] {
+	return c.AllWithdrawsContext(context.Background(), currency, state)
+}
+
+// AllWithdrawsContext is like AllWithdraws but uses ctx for cancellation and deadlines.
+func (c *Client) AllWithdrawsContext(ctx context.Context, currency string, state WithdrawState) iter.Seq2[Withdraw, error] {
+	return pages(1, maxPageLimit, func(page, limit int) ([]Withdraw, error) {
+		return c.GetWithdrawsContext(ctx, currency, string(state), nil, nil, limit, page, "desc")
+	})
+}
+
+// AllDeposits iterates over deposits, newest first, page by page. Empty
+// currency or state match all deposits.
+func (c *Client) AllDeposits(currency string, state DepositState) iter.Seq2[Deposit, error] {
+	return c.AllDepositsContext(context.Background(), currency, state)
+}
+
+// AllDepositsContext is like AllDeposits but uses ctx for cancellation and deadlines.
+func (c *Client) AllDepositsContext(ctx context.Context, currency string, state DepositState) iter.Seq2[Deposit, error] {
+	return pages(1, maxPageLimit, func(page, limit int) ([]Deposit, error) {
+		return c.GetDepositsContext(ctx, currency, string(state), nil, nil, limit, page, "desc")
+	})
+}
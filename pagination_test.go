@@ -0,0 +1,307 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAllTradesFollowsCursor(t *testing.T) {
+	const total = maxTradesCount + 20
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		next := int64(total)
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			n, _ := strconv.ParseInt(cursor, 10, 64)
+			next = n - 1
+		}
+		var trades []Trade
+		for id := next; id > 0 && len(trades) < maxTradesCount; id-- {
+			trades = append(trades, Trade{Market: "KRW-BTC", SequentialID: id})
+		}
+		json.NewEncoder(w).Encode(trades)
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRateLimiter(nil)
+
+	count, last := 0, int64(total+1)
+	for trade, err := range client.AllTrades("KRW-BTC", 0) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if trade.SequentialID >= last {
+			t.Fatalf("Expected descending sequential IDs, got %d after %d", trade.SequentialID, last)
+		}
+		last = trade.SequentialID
+		count++
+	}
+	if count != total || requests != 2 {
+		t.Errorf("Expected %d trades in 2 requests, got %d in %d", total, count, requests)
+	}
+
+	requests = 0
+	for range client.AllTrades("KRW-BTC", 0) {
+		break
+	}
+	if requests != 1 {
+		t.Errorf("Expected breaking early to stop paging, got %d requests", requests)
+	}
+}
+
+func TestAllWithdrawsPages(t *testing.T) {
+	var pagesSeen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pagesSeen = append(pagesSeen, page)
+		if r.URL.Query().Get("limit") != "100" {
+			t.Errorf("Expected limit 100, got %s", r.URL.Query().Get("limit"))
+		}
+		if r.URL.Query().Get("state") != "DONE" {
+			t.Errorf("Expected state DONE, got %s", r.URL.Query().Get("state"))
+		}
+
+		n := maxPageLimit
+		if page == "2" {
+			n = 5
+		}
+		withdraws := make([]Withdraw, n)
+		for i := range withdraws {
+			withdraws[i].UUID = fmt.Sprintf("%s-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(withdraws)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	count := 0
+	for _, err := range client.AllWithdraws("BTC", WithdrawStateDone) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		count++
+	}
+	if count != maxPageLimit+5 {
+		t.Errorf("Expected %d withdrawals, got %d", maxPageLimit+5, count)
+	}
+	if len(pagesSeen) != 2 || pagesSeen[0] != "1" || pagesSeen[1] != "2" {
+		t.Errorf("Expected pages 1 and 2, got %v", pagesSeen)
+	}
+}
+
+func TestAllOpenOrdersReusable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("market") != "KRW-BTC" || q.Get("limit") != "100" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		// Two full pages and a short one.
+		page, _ := strconv.Atoi(q.Get("page"))
+		n := maxPageLimit
+		if page == 3 {
+			n = 1
+		}
+		orders := make([]Order, n)
+		for i := range orders {
+			orders[i].UUID = fmt.Sprintf("%d-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(orders)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	// The same iterator may be ranged more than once, concurrently.
+	seq := client.AllOpenOrders(&OpenOrdersRequest{Market: "KRW-BTC"})
+	counts := make(chan int, 2)
+	for range 2 {
+		go func() {
+			n := 0
+			for _, err := range seq {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				n++
+			}
+			counts <- n
+		}()
+	}
+	for range 2 {
+		if n := <-counts; n != 2*maxPageLimit+1 {
+			t.Errorf("Expected %d orders, got %d", 2*maxPageLimit+1, n)
+		}
+	}
+}
+
+func TestAllClosedOrdersWindows(t *testing.T) {
+	end := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+	start := end.Add(-10 * 24 * time.Hour)
+
+	var windows [][2]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		windows = append(windows, [2]string{q.Get("start_time"), q.Get("end_time")})
+		// Every window returns the same order on its boundary plus its own.
+		orders := []Order{
+			{UUID: "window-" + q.Get("end_time")},
+			{UUID: "boundary"},
+		}
+		json.NewEncoder(w).Encode(orders)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	var uuids []string
+	for o, err := range client.AllClosedOrders(&ClosedOrdersRequest{Market: "KRW-BTC", Start: start, End: end}) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		uuids = append(uuids, o.UUID)
+	}
+
+	want := [][2]string{
+		{"2024-01-04T00:00:00Z", "2024-01-11T00:00:00Z"},
+		{"2024-01-01T00:00:00Z", "2024-01-04T00:00:00Z"},
+	}
+	if fmt.Sprint(windows) != fmt.Sprint(want) {
+		t.Errorf("Expected windows %v, got %v", want, windows)
+	}
+	if len(uuids) != 3 {
+		t.Errorf("Expected 3 distinct orders, got %v", uuids)
+	}
+}
+
+func TestAllClosedOrdersFullPages(t *testing.T) {
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	start := end.Add(-24 * time.Hour)
+
+	// created holds the creation times of the server's orders, newest first.
+	var created []time.Time
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		from, _ := time.Parse(time.RFC3339, q.Get("start_time"))
+		to, _ := time.Parse(time.RFC3339, q.Get("end_time"))
+		var orders []Order
+		for i, c := range created {
+			if !c.Before(from) && !c.After(to) && len(orders) < maxClosedOrdersLimit {
+				orders = append(orders, Order{UUID: strconv.Itoa(i), CreatedAt: c.Format(time.RFC3339)})
+			}
+		}
+		json.NewEncoder(w).Encode(orders)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	req := &ClosedOrdersRequest{Start: start, End: end}
+
+	// 1500 orders a second apart take two pages of the same window.
+	for i := range 1500 {
+		created = append(created, end.Add(-time.Duration(i)*time.Second))
+	}
+	orders, err := client.GetClosedOrdersRange(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(orders) != 1500 || requests != 2 {
+		t.Errorf("Expected 1500 orders in 2 requests, got %d in %d", len(orders), requests)
+	}
+
+	// More orders in one second than fit in a page cannot be paged.
+	created = created[:0]
+	for range maxClosedOrdersLimit + 1 {
+		created = append(created, end)
+	}
+	if _, err := client.GetClosedOrdersRange(req); err == nil {
+		t.Error("Expected error for a full page within one second")
+	}
+}
+
+func TestAllClosedOrdersAscending(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+
+	// One order per day; the order on the window boundary is returned by
+	// both windows.
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("order_by") != "asc" {
+			t.Errorf("Expected order_by asc, got %s", q.Get("order_by"))
+		}
+		from, _ := time.Parse(time.RFC3339, q.Get("start_time"))
+		to, _ := time.Parse(time.RFC3339, q.Get("end_time"))
+		windows = append(windows, from.Format("01-02")+"/"+to.Format("01-02"))
+
+		var orders []Order
+		for day := start; !day.After(end); day = day.Add(24 * time.Hour) {
+			if !day.Before(from) && !day.After(to) {
+				orders = append(orders, Order{UUID: day.Format("01-02"), CreatedAt: day.Format(time.RFC3339)})
+			}
+		}
+		json.NewEncoder(w).Encode(orders)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	orders, err := client.GetClosedOrdersRange(&ClosedOrdersRequest{Start: start, End: end, OrderBy: "asc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"01-01/01-08", "01-08/01-11"}; fmt.Sprint(windows) != fmt.Sprint(want) {
+		t.Errorf("Expected windows %v, got %v", want, windows)
+	}
+	if len(orders) != 11 || orders[0].UUID != "01-01" || orders[10].UUID != "01-11" {
+		t.Errorf("Expected 11 distinct orders oldest first, got %+v", orders)
+	}
+
+	_, err = client.GetClosedOrdersRange(&ClosedOrdersRequest{States: []OrderState{OrderStateWait}})
+	var vErr *OrderValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "States" {
+		t.Errorf("Expected a States validation error, got %v", err)
+	}
+
+	_, err = client.GetClosedOrdersRange(&ClosedOrdersRequest{OrderBy: "ASC"})
+	if !errors.As(err, &vErr) || vErr.Field != "OrderBy" {
+		t.Errorf("Expected an OrderBy validation error, got %v", err)
+	}
+}
+
+func TestIteratorContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var errs []error
+	for _, err := range client.AllDepositsContext(ctx, "", "") {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("Expected a single context.Canceled error, got %v", errs)
+	}
+}
@@ -1,23 +1,31 @@
 package upbit
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // === Market API ===
 
 // GetMarkets retrieves all available markets.
 func (c *Client) GetMarkets(isDetails bool) ([]Market, error) {
+	return c.GetMarketsContext(context.Background(), isDetails)
+}
+
+// GetMarketsContext is like GetMarkets but uses ctx for cancellation and deadlines.
+func (c *Client) GetMarketsContext(ctx context.Context, isDetails bool) ([]Market, error) {
 	params := url.Values{}
 	if isDetails {
 		params.Set("is_details", "true")
 	}
 
-	body, err := c.get("/market/all", params, false)
+	body, err := c.get(ctx, "/market/all", params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -45,46 +53,106 @@ const (
 	CandleUnit240 CandleUnit = 240
 )
 
-// GetMinuteCandles retrieves minute candles for a market.
-func (c *Client) GetMinuteCandles(market string, unit CandleUnit, to string, count int) ([]Candle, error) {
-	params := url.Values{}
-	params.Set("market", market)
-	if to != "" {
-		params.Set("to", to)
+// CandleInterval identifies a candle granularity. Its value is the path of
+// the candle endpoint below /candles.
+type CandleInterval string
+
+const (
+	CandleIntervalSecond    CandleInterval = "seconds"
+	CandleIntervalMinute1   CandleInterval = "minutes/1"
+	CandleIntervalMinute3   CandleInterval = "minutes/3"
+	CandleIntervalMinute5   CandleInterval = "minutes/5"
+	CandleIntervalMinute10  CandleInterval = "minutes/10"
+	CandleIntervalMinute15  CandleInterval = "minutes/15"
+	CandleIntervalMinute30  CandleInterval = "minutes/30"
+	CandleIntervalMinute60  CandleInterval = "minutes/60"
+	CandleIntervalMinute240 CandleInterval = "minutes/240"
+	CandleIntervalDay       CandleInterval = "days"
+	CandleIntervalWeek      CandleInterval = "weeks"
+	CandleIntervalMonth     CandleInterval = "months"
+	CandleIntervalYear      CandleInterval = "years"
+)
+
+// MinuteInterval returns the interval of minute candles of the given unit.
+func MinuteInterval(unit CandleUnit) CandleInterval {
+	return CandleInterval("minutes/" + strconv.Itoa(int(unit)))
+}
+
+// minuteUnit returns the unit of a minute interval.
+func (i CandleInterval) minuteUnit() (CandleUnit, bool) {
+	s, ok := strings.CutPrefix(string(i), "minutes/")
+	if !ok {
+		return 0, false
 	}
-	if count > 0 {
-		params.Set("count", strconv.Itoa(count))
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, false
 	}
+	return CandleUnit(n), true
+}
 
-	endpoint := "/candles/minutes/" + strconv.Itoa(int(unit))
-	body, err := c.get(endpoint, params, false)
-	if err != nil {
-		return nil, err
-	}
+// advance returns the start of the candle n intervals after the one
+// starting at t.
+func (i CandleInterval) advance(t time.Time, n int) time.Time {
+	switch i {
+	case CandleIntervalSecond:
+		return t.Add(time.Duration(n) * time.Second)
+	case CandleIntervalDay:
+		return t.AddDate(0, 0, n)
+	case CandleIntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case CandleIntervalMonth:
+		return t.AddDate(0, n, 0)
+	case CandleIntervalYear:
+		return t.AddDate(n, 0, 0)
+	}
+	unit, _ := i.minuteUnit()
+	return t.Add(time.Duration(n) * time.Duration(unit) * time.Minute)
+}
 
-	var candles []Candle
-	if err := json.Unmarshal(body, &candles); err != nil {
-		return nil, err
+// valid reports whether i is a known interval.
+func (i CandleInterval) valid() bool {
+	switch i {
+	case CandleIntervalSecond, CandleIntervalDay, CandleIntervalWeek, CandleIntervalMonth, CandleIntervalYear:
+		return true
 	}
-	slices.Reverse(candles)
-	return candles, nil
+	_, ok := i.minuteUnit()
+	return ok
 }
 
-// GetDayCandles retrieves daily candles for a market.
-func (c *Client) GetDayCandles(market string, to string, count int, convertingPriceUnit string) ([]Candle, error) {
+// CandleRequest represents the request parameters for GetCandles.
+type CandleRequest struct {
+	Market              string         // Market code (required)
+	Interval            CandleInterval // Candle granularity (required)
+	To                  string         // Exclusive end time in ISO 8601; empty for the latest candles
+	Count               int            // Number of candles, up to 200
+	ConvertingPriceUnit string         // Currency to convert prices to; day candles only
+}
+
+// GetCandles retrieves candles of any interval for a market, oldest first.
+func (c *Client) GetCandles(req CandleRequest) ([]Candle, error) {
+	return c.GetCandlesContext(context.Background(), req)
+}
+
+// GetCandlesContext is like GetCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetCandlesContext(ctx context.Context, req CandleRequest) ([]Candle, error) {
+	if !req.Interval.valid() {
+		return nil, fmt.Errorf("upbit: unknown candle interval %q", req.Interval)
+	}
+
 	params := url.Values{}
-	params.Set("market", market)
-	if to != "" {
-		params.Set("to", to)
+	params.Set("market", req.Market)
+	if req.To != "" {
+		params.Set("to", req.To)
 	}
-	if count > 0 {
-		params.Set("count", strconv.Itoa(count))
+	if req.Count > 0 {
+		params.Set("count", strconv.Itoa(req.Count))
 	}
-	if convertingPriceUnit != "" {
-		params.Set("converting_price_unit", convertingPriceUnit)
+	if req.ConvertingPriceUnit != "" && req.Interval == CandleIntervalDay {
+		params.Set("converting_price_unit", req.ConvertingPriceUnit)
 	}
 
-	body, err := c.get("/candles/days", params, false)
+	body, err := c.get(ctx, "/candles/"+string(req.Interval), params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -97,62 +165,85 @@ func (c *Client) GetDayCandles(market string, to string, count int, convertingPr
 	return candles, nil
 }
 
+// GetSecondCandles retrieves one-second candles for a market.
+func (c *Client) GetSecondCandles(market string, to string, count int) ([]Candle, error) {
+	return c.GetSecondCandlesContext(context.Background(), market, to, count)
+}
+
+// GetSecondCandlesContext is like GetSecondCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetSecondCandlesContext(ctx context.Context, market string, to string, count int) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{Market: market, Interval: CandleIntervalSecond, To: to, Count: count})
+}
+
+// GetMinuteCandles retrieves minute candles for a market.
+func (c *Client) GetMinuteCandles(market string, unit CandleUnit, to string, count int) ([]Candle, error) {
+	return c.GetMinuteCandlesContext(context.Background(), market, unit, to, count)
+}
+
+// GetMinuteCandlesContext is like GetMinuteCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetMinuteCandlesContext(ctx context.Context, market string, unit CandleUnit, to string, count int) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{Market: market, Interval: MinuteInterval(unit), To: to, Count: count})
+}
+
+// GetDayCandles retrieves daily candles for a market.
+func (c *Client) GetDayCandles(market string, to string, count int, convertingPriceUnit string) ([]Candle, error) {
+	return c.GetDayCandlesContext(context.Background(), market, to, count, convertingPriceUnit)
+}
+
+// GetDayCandlesContext is like GetDayCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetDayCandlesContext(ctx context.Context, market string, to string, count int, convertingPriceUnit string) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{
+		Market:              market,
+		Interval:            CandleIntervalDay,
+		To:                  to,
+		Count:               count,
+		ConvertingPriceUnit: convertingPriceUnit,
+	})
+}
+
 // GetWeekCandles retrieves weekly candles for a market.
 func (c *Client) GetWeekCandles(market string, to string, count int) ([]Candle, error) {
-	params := url.Values{}
-	params.Set("market", market)
-	if to != "" {
-		params.Set("to", to)
-	}
-	if count > 0 {
-		params.Set("count", strconv.Itoa(count))
-	}
-
-	body, err := c.get("/candles/weeks", params, false)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetWeekCandlesContext(context.Background(), market, to, count)
+}
 
-	var candles []Candle
-	if err := json.Unmarshal(body, &candles); err != nil {
-		return nil, err
-	}
-	slices.Reverse(candles)
-	return candles, nil
+// GetWeekCandlesContext is like GetWeekCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetWeekCandlesContext(ctx context.Context, market string, to string, count int) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{Market: market, Interval: CandleIntervalWeek, To: to, Count: count})
 }
 
 // GetMonthCandles retrieves monthly candles for a market.
 func (c *Client) GetMonthCandles(market string, to string, count int) ([]Candle, error) {
-	params := url.Values{}
-	params.Set("market", market)
-	if to != "" {
-		params.Set("to", to)
-	}
-	if count > 0 {
-		params.Set("count", strconv.Itoa(count))
-	}
+	return c.GetMonthCandlesContext(context.Background(), market, to, count)
+}
 
-	body, err := c.get("/candles/months", params, false)
-	if err != nil {
-		return nil, err
-	}
+// GetMonthCandlesContext is like GetMonthCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetMonthCandlesContext(ctx context.Context, market string, to string, count int) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{Market: market, Interval: CandleIntervalMonth, To: to, Count: count})
+}
 
-	var candles []Candle
-	if err := json.Unmarshal(body, &candles); err != nil {
-		return nil, err
-	}
-	slices.Reverse(candles)
-	return candles, nil
+// GetYearCandles retrieves yearly candles for a market.
+func (c *Client) GetYearCandles(market string, to string, count int) ([]Candle, error) {
+	return c.GetYearCandlesContext(context.Background(), market, to, count)
+}
+
+// GetYearCandlesContext is like GetYearCandles but uses ctx for cancellation and deadlines.
+func (c *Client) GetYearCandlesContext(ctx context.Context, market string, to string, count int) ([]Candle, error) {
+	return c.GetCandlesContext(ctx, CandleRequest{Market: market, Interval: CandleIntervalYear, To: to, Count: count})
 }
 
 // === Ticker API ===
 
 // GetTicker retrieves the current ticker for specified markets.
 func (c *Client) GetTicker(markets []string) ([]Ticker, error) {
+	return c.GetTickerContext(context.Background(), markets)
+}
+
+// GetTickerContext is like GetTicker but uses ctx for cancellation and deadlines.
+func (c *Client) GetTickerContext(ctx context.Context, markets []string) ([]Ticker, error) {
 	params := url.Values{}
 	params.Set("markets", strings.Join(markets, ","))
 
-	body, err := c.get("/ticker", params, false)
+	body, err := c.get(ctx, "/ticker", params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -166,12 +257,17 @@ func (c *Client) GetTicker(markets []string) ([]Ticker, error) {
 
 // GetAllTickers retrieves tickers for all markets of specified quote currencies.
 func (c *Client) GetAllTickers(quoteCurrencies []string) ([]Ticker, error) {
+	return c.GetAllTickersContext(context.Background(), quoteCurrencies)
+}
+
+// GetAllTickersContext is like GetAllTickers but uses ctx for cancellation and deadlines.
+func (c *Client) GetAllTickersContext(ctx context.Context, quoteCurrencies []string) ([]Ticker, error) {
 	params := url.Values{}
 	if len(quoteCurrencies) > 0 {
 		params.Set("quote_currencies", strings.Join(quoteCurrencies, ","))
 	}
 
-	body, err := c.get("/ticker/all", params, false)
+	body, err := c.get(ctx, "/ticker/all", params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -187,13 +283,18 @@ func (c *Client) GetAllTickers(quoteCurrencies []string) ([]Ticker, error) {
 
 // GetOrderbook retrieves the orderbook for specified markets.
 func (c *Client) GetOrderbook(markets []string, level int) ([]Orderbook, error) {
+	return c.GetOrderbookContext(context.Background(), markets, level)
+}
+
+// GetOrderbookContext is like GetOrderbook but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrderbookContext(ctx context.Context, markets []string, level int) ([]Orderbook, error) {
 	params := url.Values{}
 	params.Set("markets", strings.Join(markets, ","))
 	if level > 0 {
 		params.Set("level", strconv.Itoa(level))
 	}
 
-	body, err := c.get("/orderbook", params, false)
+	body, err := c.get(ctx, "/orderbook", params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -209,6 +310,11 @@ func (c *Client) GetOrderbook(markets []string, level int) ([]Orderbook, error)
 
 // GetTrades retrieves recent trades for a market.
 func (c *Client) GetTrades(market string, to string, count int, cursor string, daysAgo int) ([]Trade, error) {
+	return c.GetTradesContext(context.Background(), market, to, count, cursor, daysAgo)
+}
+
+// GetTradesContext is like GetTrades but uses ctx for cancellation and deadlines.
+func (c *Client) GetTradesContext(ctx context.Context, market string, to string, count int, cursor string, daysAgo int) ([]Trade, error) {
 	params := url.Values{}
 	params.Set("market", market)
 	if to != "" {
@@ -224,7 +330,7 @@ func (c *Client) GetTrades(market string, to string, count int, cursor string, d
 		params.Set("days_ago", strconv.Itoa(daysAgo))
 	}
 
-	body, err := c.get("/trades/ticks", params, false)
+	body, err := c.get(ctx, "/trades/ticks", params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -235,3 +341,36 @@ func (c *Client) GetTrades(market string, to string, count int, cursor string, d
 	}
 	return trades, nil
 }
+
+// maxTradesCount is the largest page size accepted by the trades endpoint.
+const maxTradesCount = 500
+
+// maxTradesDaysAgo is the oldest day, counted back from today, served by
+// the trades endpoint.
+const maxTradesDaysAgo = 7
+
+// TradesSince retrieves trades of a market newer than the trade with the
+// given sequential ID, oldest first. It is intended to backfill trades
+// missed during a stream reconnection (see GapEvent). It walks back through
+// up to seven previous days and fails if the trade is not found by then.
+func (c *Client) TradesSince(market string, sequentialID int64) ([]Trade, error) {
+	return c.TradesSinceContext(context.Background(), market, sequentialID)
+}
+
+// TradesSinceContext is like TradesSince but uses ctx for cancellation and deadlines.
+func (c *Client) TradesSinceContext(ctx context.Context, market string, sequentialID int64) ([]Trade, error) {
+	var trades []Trade
+	for daysAgo := 0; daysAgo <= maxTradesDaysAgo; daysAgo++ {
+		for t, err := range c.AllTradesContext(ctx, market, daysAgo) {
+			if err != nil {
+				return nil, err
+			}
+			if t.SequentialID <= sequentialID {
+				slices.Reverse(trades)
+				return trades, nil
+			}
+			trades = append(trades, t)
+		}
+	}
+	return nil, fmt.Errorf("upbit: trade %d of %s is older than %d days", sequentialID, market, maxTradesDaysAgo)
+}
@@ -0,0 +1,237 @@
+package upbit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitGroup identifies an Upbit rate limit group as reported in the
+// Remaining-Req response header.
+type RateLimitGroup string
+
+const (
+	RateLimitGroupMarket    RateLimitGroup = "market"           // Market list
+	RateLimitGroupCandles   RateLimitGroup = "candles"          // Candles
+	RateLimitGroupTicker    RateLimitGroup = "ticker"           // Tickers
+	RateLimitGroupOrderbook RateLimitGroup = "orderbook"        // Orderbooks
+	RateLimitGroupTrades    RateLimitGroup = "trades"           // Recent trades
+	RateLimitGroupDefault   RateLimitGroup = "default"          // Exchange API (accounts, withdrawals, ...)
+	RateLimitGroupOrder     RateLimitGroup = "order"            // Order placement and replacement
+	RateLimitGroupCancelAll RateLimitGroup = "order-cancel-all" // Cancelling all open orders
+)
+
+// defaultRateLimits holds the documented per-second limits of each group.
+var defaultRateLimits = map[RateLimitGroup]float64{
+	RateLimitGroupMarket:    10,
+	RateLimitGroupCandles:   10,
+	RateLimitGroupTicker:    10,
+	RateLimitGroupOrderbook: 10,
+	RateLimitGroupTrades:    10,
+	RateLimitGroupDefault:   30,
+	RateLimitGroupOrder:     8,
+	RateLimitGroupCancelAll: 0.5, // one request every two seconds
+}
+
+// fallbackRateLimit applies to groups without a documented limit.
+const fallbackRateLimit = 10
+
+// RemainingReq is the parsed value of the Remaining-Req response header.
+type RemainingReq struct {
+	Group     RateLimitGroup // Rate limit group
+	Min       int            // Requests remaining in the current minute (-1 if not reported)
+	Sec       int            // Requests remaining in the current second
+	UpdatedAt time.Time      // When the header was received
+}
+
+// ParseRemainingReq parses a Remaining-Req header value such as
+// "group=default; min=1799; sec=29".
+func ParseRemainingReq(value string) (RemainingReq, bool) {
+	r := RemainingReq{Min: -1, Sec: -1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "group":
+			r.Group = RateLimitGroup(val)
+		case "min":
+			if n, err := strconv.Atoi(val); err == nil {
+				r.Min = n
+			}
+		case "sec":
+			if n, err := strconv.Atoi(val); err == nil {
+				r.Sec = n
+			}
+		}
+	}
+	if r.Group == "" || r.Sec < 0 {
+		return RemainingReq{}, false
+	}
+	return r, true
+}
+
+// bucket is a token bucket for a single rate limit group.
+type bucket struct {
+	rate         float64   // tokens added per second
+	tokens       float64   // currently available tokens
+	last         time.Time // last refill time
+	blockedUntil time.Time // no tokens are handed out before this time
+}
+
+// capacity is the largest number of tokens the bucket holds. Groups limited
+// to less than one request per second still allow a single request.
+func (b *bucket) capacity() float64 {
+	return max(b.rate, 1)
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.capacity(), b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// RateLimiter throttles requests per rate limit group using token buckets
+// that are reconciled with the Remaining-Req header of every response.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limits    map[RateLimitGroup]float64
+	buckets   map[RateLimitGroup]*bucket
+	remaining map[RateLimitGroup]RemainingReq
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter using Upbit's documented limits.
+func NewRateLimiter() *RateLimiter {
+	limits := make(map[RateLimitGroup]float64, len(defaultRateLimits))
+	for g, n := range defaultRateLimits {
+		limits[g] = n
+	}
+	return &RateLimiter{
+		limits:    limits,
+		buckets:   make(map[RateLimitGroup]*bucket),
+		remaining: make(map[RateLimitGroup]RemainingReq),
+		now:       time.Now,
+	}
+}
+
+// SetLimit overrides the per-second limit of a group.
+func (l *RateLimiter) SetLimit(group RateLimitGroup, perSecond int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits[group] = float64(perSecond)
+	if b, ok := l.buckets[group]; ok {
+		b.rate = float64(perSecond)
+		b.tokens = min(b.tokens, b.capacity())
+	}
+}
+
+// bucketLocked returns the bucket for group, creating a full one if needed.
+func (l *RateLimiter) bucketLocked(group RateLimitGroup) *bucket {
+	b, ok := l.buckets[group]
+	if !ok {
+		rate, ok := l.limits[group]
+		if !ok || rate <= 0 {
+			rate = fallbackRateLimit
+		}
+		b = &bucket{rate: rate, last: l.now()}
+		b.tokens = b.capacity()
+		l.buckets[group] = b
+	}
+	return b
+}
+
+// Wait blocks until a request in group may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, group RateLimitGroup) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		b := l.bucketLocked(group)
+		var delay time.Duration
+		if now.Before(b.blockedUntil) {
+			delay = b.blockedUntil.Sub(now)
+		} else {
+			b.refill(now)
+			if b.tokens >= 1 {
+				b.tokens--
+				l.mu.Unlock()
+				return nil
+			}
+			delay = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Update records a Remaining-Req value and caps the group's bucket to the
+// quota reported by the server.
+func (l *RateLimiter) Update(r RemainingReq) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.remaining[r.Group] = r
+	now := l.now()
+	b := l.bucketLocked(r.Group)
+	b.refill(now)
+	b.tokens = min(b.tokens, float64(r.Sec))
+	if r.Sec == 0 {
+		// The server-side window resets at the next second.
+		b.blockedUntil = now.Add(time.Second)
+	}
+}
+
+// Remaining returns the last quota reported for group.
+func (l *RateLimiter) Remaining(group RateLimitGroup) (RemainingReq, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.remaining[group]
+	return r, ok
+}
+
+// observe updates the limiter from a response's headers.
+func (l *RateLimiter) observe(header http.Header) {
+	r, ok := ParseRemainingReq(header.Get("Remaining-Req"))
+	if !ok {
+		return
+	}
+	r.UpdatedAt = l.now()
+	l.Update(r)
+}
+
+// rateLimitGroupFor returns the rate limit group an endpoint belongs to.
+func rateLimitGroupFor(method, endpoint string) RateLimitGroup {
+	switch {
+	case endpoint == "/market/all":
+		return RateLimitGroupMarket
+	case strings.HasPrefix(endpoint, "/candles/"):
+		return RateLimitGroupCandles
+	case strings.HasPrefix(endpoint, "/ticker"):
+		return RateLimitGroupTicker
+	case endpoint == "/orderbook":
+		return RateLimitGroupOrderbook
+	case strings.HasPrefix(endpoint, "/trades/"):
+		return RateLimitGroupTrades
+	case method == http.MethodPost && (endpoint == "/orders" || endpoint == "/orders/test" || endpoint == "/orders/cancel_and_new"):
+		return RateLimitGroupOrder
+	case method == http.MethodDelete && endpoint == "/orders/open":
+		return RateLimitGroupCancelAll
+	default:
+		return RateLimitGroupDefault
+	}
+}
@@ -0,0 +1,136 @@
+package upbit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestParseRemainingReq(t *testing.T) {
+	r, ok := ParseRemainingReq("group=default; min=1799; sec=29")
+	if !ok {
+		t.Fatal("Expected header to parse")
+	}
+	if r.Group != RateLimitGroupDefault || r.Min != 1799 || r.Sec != 29 {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+
+	r, ok = ParseRemainingReq("group=order; sec=7")
+	if !ok {
+		t.Fatal("Expected header without min to parse")
+	}
+	if r.Min != -1 || r.Sec != 7 {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+
+	if _, ok := ParseRemainingReq("garbage"); ok {
+		t.Error("Expected invalid header to be rejected")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit(RateLimitGroupOrder, 2)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, RateLimitGroupOrder); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, RateLimitGroupOrder); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected exhausted bucket to block, got %v", err)
+	}
+}
+
+func TestRateLimiterCancelAllGroup(t *testing.T) {
+	if g := rateLimitGroupFor(http.MethodDelete, "/orders/open"); g != RateLimitGroupCancelAll {
+		t.Errorf("Expected group %s, got %s", RateLimitGroupCancelAll, g)
+	}
+
+	// The group allows one request every two seconds.
+	limiter := NewRateLimiter()
+	ctx := context.Background()
+	if err := limiter.Wait(ctx, RateLimitGroupCancelAll); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, RateLimitGroupCancelAll); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the second request to block, got %v", err)
+	}
+}
+
+func TestRateLimiterUpdateBlocksOnZero(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.Update(RemainingReq{Group: RateLimitGroupTicker, Sec: 0})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, RateLimitGroupTicker); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected group to be blocked after sec=0, got %v", err)
+	}
+}
+
+func TestClientRemainingRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Remaining-Req", "group=ticker; min=599; sec=9")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	if _, err := client.GetTicker([]string{"KRW-BTC"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	r, ok := client.RemainingRequests(RateLimitGroupTicker)
+	if !ok {
+		t.Fatal("Expected remaining quota to be recorded")
+	}
+	if r.Sec != 9 || r.Min != 599 {
+		t.Errorf("Unexpected remaining quota: %+v", r)
+	}
+}
+
+func TestTokenSignedAfterLimiterWait(t *testing.T) {
+	var signedAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, err := jwt.Parse(raw, func(*jwt.Token) (any, error) { return []byte("secret"), nil })
+		if err != nil {
+			t.Errorf("Expected valid token, got %v", err)
+		} else if ms, ok := token.Claims.(jwt.MapClaims)["timestamp"].(float64); ok {
+			signedAt = time.UnixMilli(int64(ms))
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	// Drain the default group so the request waits about 200ms.
+	limiter := NewRateLimiter()
+	limiter.SetLimit(RateLimitGroupDefault, 5)
+	for range 5 {
+		limiter.Wait(context.Background(), RateLimitGroupDefault)
+	}
+	client := NewClient("access", "secret", WithBaseURL(server.URL+"/v1"), WithRateLimiter(limiter))
+
+	start := time.Now()
+	if _, err := client.GetAccounts(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if signedAt.Before(start.Add(150 * time.Millisecond)) {
+		t.Errorf("Expected the token to be signed after the limiter wait, signed %v after start", signedAt.Sub(start))
+	}
+}
@@ -0,0 +1,109 @@
+package upbit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Region identifies an Upbit exchange. Regions differ in API hosts, fiat
+// currency and the quote currencies their markets trade against.
+type Region string
+
+const (
+	RegionKR Region = "KR" // Upbit Korea
+	RegionSG Region = "SG" // Upbit Singapore
+	RegionID Region = "ID" // Upbit Indonesia
+	RegionTH Region = "TH" // Upbit Thailand
+)
+
+// regionInfo holds the endpoints and currencies of a region.
+type regionInfo struct {
+	host   string
+	fiat   string
+	quotes []string
+}
+
+var regions = map[Region]regionInfo{
+	RegionKR: {host: "api.upbit.com", fiat: "KRW", quotes: []string{"KRW", "BTC", "USDT"}},
+	RegionSG: {host: "sg-api.upbit.com", fiat: "SGD", quotes: []string{"SGD", "BTC", "USDT"}},
+	RegionID: {host: "id-api.upbit.com", fiat: "IDR", quotes: []string{"IDR", "BTC", "USDT"}},
+	RegionTH: {host: "th-api.upbit.com", fiat: "THB", quotes: []string{"THB", "BTC", "USDT"}},
+}
+
+// ParseRegion parses a region code such as "sg", ignoring case.
+func ParseRegion(s string) (Region, error) {
+	r := Region(strings.ToUpper(s))
+	if !r.IsValid() {
+		return "", fmt.Errorf("upbit: unknown region %q", s)
+	}
+	return r, nil
+}
+
+// IsValid reports whether r is a known region.
+func (r Region) IsValid() bool {
+	_, ok := regions[r]
+	return ok
+}
+
+func (r Region) String() string { return string(r) }
+
+// BaseURL returns the REST API base URL of r, or "" if r is unknown.
+func (r Region) BaseURL() string {
+	info, ok := regions[r]
+	if !ok {
+		return ""
+	}
+	return "https://" + info.host + "/v1"
+}
+
+// WebSocketURL returns the WebSocket base URL of r, or "" if r is unknown.
+func (r Region) WebSocketURL() string {
+	info, ok := regions[r]
+	if !ok {
+		return ""
+	}
+	return "wss://" + info.host + "/websocket/v1"
+}
+
+// FiatCurrency returns the fiat currency of r, e.g. "SGD".
+func (r Region) FiatCurrency() string {
+	return regions[r].fiat
+}
+
+// QuoteCurrencies returns the quote currencies of r's markets.
+func (r Region) QuoteCurrencies() []string {
+	return slices.Clone(regions[r].quotes)
+}
+
+// ValidateMarket checks that market is a well-formed market code such as
+// "SGD-BTC" whose quote currency is traded in r. The error is an
+// *OrderValidationError matching ErrInvalidMarket.
+func (r Region) ValidateMarket(market string) error {
+	info, ok := regions[r]
+	if !ok {
+		return invalidOrder(ErrInvalidMarket, "Market", "unknown region %q", r)
+	}
+	quote, base, ok := strings.Cut(market, "-")
+	if !ok || !isCurrencyCode(quote) || !isCurrencyCode(base) {
+		return invalidOrder(ErrInvalidMarket, "Market", "malformed market code %q", market)
+	}
+	if !slices.Contains(info.quotes, quote) {
+		return invalidOrder(ErrInvalidMarket, "Market", "quote currency %s is not traded in region %s", quote, r)
+	}
+	return nil
+}
+
+// isCurrencyCode reports whether s is a non-empty run of upper-case letters
+// and digits, like "BTC" or "1INCH".
+func isCurrencyCode(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
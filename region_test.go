@@ -0,0 +1,205 @@
+package upbit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegion(t *testing.T) {
+	if RegionKR.BaseURL() != BaseURL || RegionKR.WebSocketURL() != WebSocketURL {
+		t.Error("Expected RegionKR to use the default URLs")
+	}
+	if got := RegionSG.BaseURL(); got != "https://sg-api.upbit.com/v1" {
+		t.Errorf("Expected SG base URL, got %s", got)
+	}
+	if got := RegionTH.WebSocketURL(); got != "wss://th-api.upbit.com/websocket/v1" {
+		t.Errorf("Expected TH WebSocket URL, got %s", got)
+	}
+	if RegionID.FiatCurrency() != "IDR" {
+		t.Errorf("Expected IDR, got %s", RegionID.FiatCurrency())
+	}
+
+	if r, err := ParseRegion("sg"); err != nil || r != RegionSG {
+		t.Errorf("Expected SG, got %s, %v", r, err)
+	}
+	if _, err := ParseRegion("jp"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+
+	tests := []struct {
+		region Region
+		market string
+		valid  bool
+	}{
+		{RegionKR, "KRW-BTC", true},
+		{RegionKR, "USDT-1INCH", true},
+		{RegionKR, "SGD-BTC", false},
+		{RegionSG, "SGD-BTC", true},
+		{RegionSG, "KRW-BTC", false},
+		{RegionID, "IDR-ETH", true},
+		{RegionTH, "THB-XRP", true},
+		{RegionTH, "BTC-ETH", true},
+		{RegionTH, "thb-xrp", false},
+		{RegionTH, "THB", false},
+		{RegionTH, "THB-", false},
+		{RegionTH, "THB-XRP-X", false},
+	}
+	for _, tt := range tests {
+		err := tt.region.ValidateMarket(tt.market)
+		if tt.valid && err != nil {
+			t.Errorf("%s %s: unexpected error %v", tt.region, tt.market, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidMarket) {
+			t.Errorf("%s %s: expected invalid_market, got %v", tt.region, tt.market, err)
+		}
+	}
+}
+
+func TestRegionTickTables(t *testing.T) {
+	tests := []struct {
+		region              Region
+		market, price, want string
+	}{
+		{RegionKR, "KRW-BTC", "150000000", "1000"},
+		{RegionKR, "KRW-ETH", "1500500", "500"},
+		{RegionKR, "KRW-XRP", "150.1", "0.1"},
+		{RegionKR, "BTC-ETH", "0.05", "0.00000001"},
+		{RegionKR, "USDT-BTC", "65000.5", "0.01"},
+		{RegionSG, "SGD-BTC", "130000", "1"},
+		{RegionSG, "SGD-ETH", "500", "0.1"},
+		{RegionSG, "SGD-XRP", "3.2", "0.001"},
+		{RegionSG, "SGD-SHIB", "0.00002", "0.00001"},
+		{RegionSG, "USDT-BTC", "65000.5", "0.01"},
+		{RegionID, "IDR-BTC", "1600000000", "10000"},
+		{RegionID, "IDR-ETH", "50000000", "1000"},
+		{RegionID, "IDR-XRP", "35000", "1"},
+		{RegionID, "IDR-DOGE", "2500", "0.1"},
+		{RegionID, "BTC-ETH", "0.05", "0.00000001"},
+		{RegionTH, "THB-BTC", "3500000", "10"},
+		{RegionTH, "THB-ETH", "12000", "1"},
+		{RegionTH, "THB-XRP", "75.5", "0.001"},
+		{RegionTH, "THB-SHIB", "0.0003", "0.00001"},
+		{RegionTH, "USDT-ETH", "3500", "0.01"},
+	}
+	for _, tt := range tests {
+		got, err := tt.region.TickSize(tt.market, MustParseDecimal(tt.price))
+		if err != nil {
+			t.Errorf("%s TickSize(%s, %s): unexpected error %v", tt.region, tt.market, tt.price, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("%s TickSize(%s, %s) = %s, want %s", tt.region, tt.market, tt.price, got, tt.want)
+		}
+	}
+
+	// Fiat tables are only available in their own region.
+	for _, tt := range []struct {
+		region Region
+		market string
+	}{
+		{RegionKR, "SGD-BTC"},
+		{RegionSG, "KRW-BTC"},
+		{RegionID, "THB-BTC"},
+		{RegionTH, "IDR-BTC"},
+	} {
+		if _, err := tt.region.TickSize(tt.market, DecimalFromInt(1)); err == nil {
+			t.Errorf("%s TickSize(%s): expected error", tt.region, tt.market)
+		}
+	}
+
+	// Registering a table in one region leaves the others unchanged.
+	RegionSG.RegisterTickTable("USDT", TickTable{{MinPrice: DecimalFromInt(0), TickSize: MustParseDecimal("0.1")}})
+	defer RegionSG.RegisterTickTable("USDT", usdtTicks)
+	if got, _ := RegionSG.TickSize("USDT-BTC", DecimalFromInt(65000)); got.String() != "0.1" {
+		t.Errorf("Expected registered SG tick 0.1, got %s", got)
+	}
+	if got, _ := RegionKR.TickSize("USDT-BTC", DecimalFromInt(65000)); got.String() != "0.01" {
+		t.Errorf("Expected KR tick 0.01, got %s", got)
+	}
+}
+
+func TestRegionValidateOrder(t *testing.T) {
+	limit := func(market, price string) *PlaceOrderRequest {
+		return &PlaceOrderRequest{
+			Market:  market,
+			Side:    OrderSideBid,
+			OrdType: OrderTypeLimit,
+			Price:   MustParseDecimal(price),
+			Volume:  DecimalFromInt(1),
+		}
+	}
+	tests := []struct {
+		region Region
+		req    *PlaceOrderRequest
+		code   ErrorCode
+	}{
+		{RegionKR, limit("KRW-BTC", "150000000"), ""},
+		{RegionKR, limit("KRW-BTC", "150000500"), ErrInvalidPrice},
+		{RegionKR, limit("SGD-BTC", "130000"), ErrInvalidMarket},
+		{RegionSG, limit("SGD-BTC", "130000"), ""},
+		{RegionSG, limit("SGD-BTC", "130000.5"), ErrInvalidPrice},
+		{RegionSG, limit("KRW-BTC", "150000000"), ErrInvalidMarket},
+		{RegionID, limit("IDR-BTC", "1600000000"), ""},
+		{RegionID, limit("IDR-BTC", "1600005000"), ErrInvalidPrice},
+		{RegionTH, limit("THB-XRP", "75.5"), ""},
+		{RegionTH, limit("THB-XRP", "75.5005"), ErrInvalidPrice},
+	}
+	for _, tt := range tests {
+		err := tt.region.ValidateOrder(tt.req, nil)
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("%s %s %s: unexpected error %v", tt.region, tt.req.Market, tt.req.Price, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.code) {
+			t.Errorf("%s %s %s: expected %s, got %v", tt.region, tt.req.Market, tt.req.Price, tt.code, err)
+		}
+	}
+}
+
+func TestWithRegion(t *testing.T) {
+	client := NewClient("access", "secret", WithRegion(RegionSG))
+	if client.Region() != RegionSG || client.baseURL != RegionSG.BaseURL() || client.wsURL != RegionSG.WebSocketURL() {
+		t.Errorf("Unexpected client URLs %s %s", client.baseURL, client.wsURL)
+	}
+
+	client = NewClient("access", "secret", WithBaseURL("http://localhost/v1"), WithRegion(RegionTH))
+	if client.baseURL != "http://localhost/v1" || client.wsURL != RegionTH.WebSocketURL() {
+		t.Errorf("Expected WithBaseURL to take precedence, got %s %s", client.baseURL, client.wsURL)
+	}
+
+	// A market of another region is rejected before the order chance is
+	// requested, so no server is needed.
+	client.SetOrderValidation(true)
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		OrdType: OrderTypeLimit,
+		Price:   DecimalFromInt(50000000),
+		Volume:  MustParseDecimal("0.001"),
+	})
+	var verr *OrderValidationError
+	if !errors.As(err, &verr) || verr.Code != ErrInvalidMarket {
+		t.Errorf("Expected invalid_market validation error, got %v", err)
+	}
+}
+
+func TestUnknownRegion(t *testing.T) {
+	if Region("JP").BaseURL() != "" || Region("JP").WebSocketURL() != "" {
+		t.Error("Expected no URLs for an unknown region")
+	}
+	if err := Region("JP").ValidateMarket("KRW-BTC"); !errors.Is(err, ErrInvalidMarket) {
+		t.Errorf("Expected invalid_market for an unknown region, got %v", err)
+	}
+
+	client := NewClient("access", "secret", WithRegion("JP"))
+	if _, err := client.GetMarkets(false); err == nil || !strings.Contains(err.Error(), "unknown region") {
+		t.Errorf("Expected unknown region error, got %v", err)
+	}
+	if err := client.NewStream().Connect(context.Background()); err == nil || !strings.Contains(err.Error(), "unknown region") {
+		t.Errorf("Expected unknown region error from stream, got %v", err)
+	}
+}
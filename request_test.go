@@ -0,0 +1,161 @@
+package upbit
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// verifyQueryHash checks the query_hash claim of r's token against the hash
+// of canonical, as the server recomputes it.
+func verifyQueryHash(t *testing.T, r *http.Request, canonical string) {
+	t.Helper()
+	raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token, err := jwt.Parse(raw, func(*jwt.Token) (any, error) {
+		return []byte("secret"), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected valid token, got %v", err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+
+	sum := sha512.Sum512([]byte(canonical))
+	if claims["query_hash"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected query_hash of %q", canonical)
+	}
+	if claims["query_hash_alg"] != "SHA512" {
+		t.Errorf("Expected query_hash_alg SHA512, got %v", claims["query_hash_alg"])
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	params := url.Values{}
+	params.Add("states[]", "wait")
+	params.Add("states[]", "watch")
+	params.Set("start_time", "2024-01-01T00:00:00+09:00")
+	params.Set("market", "KRW-BTC")
+
+	want := "market=KRW-BTC&start_time=2024-01-01T00:00:00+09:00&states[]=wait&states[]=watch"
+	if got := canonicalQuery(params); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// The server hashes the unescaped form of what was sent.
+	sent, err := url.QueryUnescape(params.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != want {
+		t.Errorf("Expected encoded query to unescape to %q, got %q", want, sent)
+	}
+}
+
+func TestEncodeJSONBody(t *testing.T) {
+	params := url.Values{}
+	params.Set("market", "KRW-BTC")
+	params.Add("uuids[]", "a")
+	params.Add("uuids[]", "b")
+
+	body, err := encodeJSONBody(params)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := `{"market":"KRW-BTC","uuids":["a","b"]}`; string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+}
+
+func TestQueryHashArrayParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		canonical, err := url.QueryUnescape(r.URL.RawQuery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "states[]=wait&states[]=watch&uuids[]=a&uuids[]=b"; canonical != want {
+			t.Errorf("Expected query %q, got %q", want, canonical)
+		}
+		verifyQueryHash(t, r, canonical)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.GetOrders(&GetOrdersRequest{
+		UUIDs:  []string{"a", "b"},
+		States: []OrderState{OrderStateWait, OrderStateWatch},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPostSendsJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("Expected JSON content type, got %q", ct)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("Expected empty query string, got %q", r.URL.RawQuery)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var fields map[string]string
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatalf("Expected JSON object body, got %s", body)
+		}
+		if fields["market"] != "KRW-BTC" || fields["volume"] != "0.01" || fields["price"] != "50000000" {
+			t.Errorf("Unexpected body %s", body)
+		}
+		verifyQueryHash(t, r, "market=KRW-BTC&ord_type=limit&price=50000000&side=bid&volume=0.01")
+		w.Write([]byte(`{"uuid":"order-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		OrdType: OrderTypeLimit,
+		Volume:  MustParseDecimal("0.01"),
+		Price:   DecimalFromInt(50000000),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestDeleteSendsQueryString(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE, got %s", r.Method)
+		}
+		if body, _ := io.ReadAll(r.Body); len(body) != 0 {
+			t.Errorf("Expected empty body, got %s", body)
+		}
+		if r.URL.RawQuery != "uuid=order-1" {
+			t.Errorf("Expected query uuid=order-1, got %q", r.URL.RawQuery)
+		}
+		verifyQueryHash(t, r, "uuid=order-1")
+		w.Write([]byte(`{"uuid":"order-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+
+	if _, err := client.CancelOrder("order-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package upbit
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Retries apply to
+// HTTP 429 and 5xx responses and to transient network errors.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; 1 or less disables retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled on each subsequent retry
+	MaxDelay    time.Duration // Upper bound on a single backoff delay
+
+	// RetryOrders enables retries for PlaceOrder when the request carries an
+	// Identifier. Before each retry the order is looked up by identifier so
+	// that an order accepted by the server is never placed twice.
+	RetryOrders bool
+}
+
+// DefaultRetryPolicy returns the policy used by new clients: up to three
+// attempts for GET requests, with order placement never retried.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// backoff returns the delay before retry number attempt (starting at 1),
+// using exponential backoff with jitter. A server-provided Retry-After
+// delay takes precedence when it is longer.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d > 0 {
+		d = d/2 + rand.N(d/2+1)
+	}
+	return max(d, retryAfter)
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying.
+func shouldRetry(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && IsRetryable(err)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
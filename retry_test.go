@@ -0,0 +1,213 @@
+package upbit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"name":"server_error","message":"try again"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode([]Ticker{{Market: "KRW-BTC"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRetryPolicy(testRetryPolicy())
+
+	tickers, err := client.GetTicker([]string{"KRW-BTC"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tickers) != 1 {
+		t.Errorf("Expected 1 ticker, got %d", len(tickers))
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"name":"invalid_parameter","message":"bad"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetRetryPolicy(testRetryPolicy())
+
+	if _, err := client.GetTicker([]string{"KRW-BTC"}); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestPlaceOrderNotRetriedWithoutIdentifier(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	policy := testRetryPolicy()
+	policy.RetryOrders = true
+	client.SetRetryPolicy(policy)
+
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		Volume:  MustParseDecimal("0.0001"),
+		Price:   DecimalFromInt(50000000),
+		OrdType: OrderTypeLimit,
+	})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestPlaceOrderRetryDeduplicates(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/orders":
+			// The order is accepted but the response is lost.
+			posts.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/order":
+			if r.URL.Query().Get("identifier") != "my-order-1" {
+				t.Errorf("Unexpected identifier %q", r.URL.Query().Get("identifier"))
+			}
+			json.NewEncoder(w).Encode(Order{UUID: "placed-uuid", State: "wait"})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var calls []string
+	hook := &recordingHook{name: "hook", calls: &calls}
+	client := NewClient("access", "secret", WithHooks(hook))
+	client.SetBaseURL(server.URL + "/v1")
+	policy := testRetryPolicy()
+	policy.RetryOrders = true
+	client.SetRetryPolicy(policy)
+
+	order, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:     "KRW-BTC",
+		Side:       OrderSideBid,
+		Volume:     MustParseDecimal("0.0001"),
+		Price:      DecimalFromInt(50000000),
+		OrdType:    OrderTypeLimit,
+		Identifier: "my-order-1",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.UUID != "placed-uuid" {
+		t.Errorf("Expected UUID 'placed-uuid', got '%s'", order.UUID)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("Expected 1 placement, got %d", posts.Load())
+	}
+	// The lookup ends first; the placement then reports the lookup's
+	// response rather than the failed attempt.
+	if len(hook.ends) != 2 || hook.ends[1].StatusCode != http.StatusOK {
+		t.Errorf("Expected the placement to end with status 200, got %+v", hook.ends)
+	}
+}
+
+func TestPlaceOrderRetryLookupError(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/orders":
+			posts.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/order":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"name":"invalid_access_key","message":"bad key"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	policy := testRetryPolicy()
+	policy.RetryOrders = true
+	client.SetRetryPolicy(policy)
+
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:     "KRW-BTC",
+		Side:       OrderSideBid,
+		Volume:     MustParseDecimal("0.0001"),
+		Price:      DecimalFromInt(50000000),
+		OrdType:    OrderTypeLimit,
+		Identifier: "my-order-1",
+	})
+	var lookupErr, placeErr *APIError
+	if !errors.As(err, &lookupErr) || lookupErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the lookup error, got %v", err)
+	}
+	wrapped, ok := err.(interface{ Unwrap() []error })
+	if !ok || !errors.As(wrapped.Unwrap()[1], &placeErr) || placeErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the placement error to be wrapped, got %v", err)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("Expected 1 placement, got %d", posts.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("2", now); d != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", d)
+	}
+	if d := parseRetryAfter(now.Add(3*time.Second).Format(http.TimeFormat), now); d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v", d)
+	}
+	if d := parseRetryAfter("soon", now); d != 0 {
+		t.Errorf("Expected 0, got %v", d)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 6; attempt++ {
+		d := policy.backoff(attempt, 0)
+		if d < 0 || d > time.Second {
+			t.Errorf("Attempt %d: delay %v out of range", attempt, d)
+		}
+	}
+	if d := policy.backoff(1, 3*time.Second); d != 3*time.Second {
+		t.Errorf("Expected Retry-After to take precedence, got %v", d)
+	}
+}
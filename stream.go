@@ -0,0 +1,647 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	WebSocketURL = "wss://api.upbit.com/websocket/v1" // WebSocket base URL of RegionKR
+)
+
+// streamBufferSize is the capacity of a Stream's event channel.
+const streamBufferSize = 256
+
+// ErrStreamClosed is returned when using a Stream after Close.
+var ErrStreamClosed = errors.New("upbit: stream closed")
+
+// StreamType identifies the kind of data carried by a WebSocket subscription.
+type StreamType string
+
+const (
+	StreamTypeTicker    StreamType = "ticker"    // Current price
+	StreamTypeTrade     StreamType = "trade"     // Trade executions
+	StreamTypeOrderbook StreamType = "orderbook" // Orderbook snapshots
+	StreamTypeMyOrder   StreamType = "myOrder"   // Own order and fill updates (private)
+	StreamTypeMyAsset   StreamType = "myAsset"   // Own balance updates (private)
+)
+
+// Subscription describes the data requested from a Stream.
+type Subscription struct {
+	Type           StreamType // Data type (required)
+	Codes          []string   // Market codes (e.g., "KRW-BTC")
+	IsOnlySnapshot bool       // Receive only snapshot messages
+	IsOnlyRealtime bool       // Receive only real-time messages
+}
+
+// Event is a message delivered by a Stream. Use a type switch to inspect
+// the concrete event, e.g. *TickerEvent.
+type Event interface {
+	isEvent()
+}
+
+// TickerEvent is a real-time ticker message.
+type TickerEvent struct {
+	Ticker
+	Code               string  `json:"code"`
+	AskBid             AskBid  `json:"ask_bid"`
+	AccAskVolume       Decimal `json:"acc_ask_volume"`
+	AccBidVolume       Decimal `json:"acc_bid_volume"`
+	MarketState        string  `json:"market_state"`
+	IsTradingSuspended bool    `json:"is_trading_suspended"`
+	DelistingDate      string  `json:"delisting_date,omitempty"`
+	MarketWarning      string  `json:"market_warning"`
+	StreamType         string  `json:"stream_type"` // SNAPSHOT or REALTIME
+}
+
+// TradeEvent is a real-time trade message.
+type TradeEvent struct {
+	Trade
+	Code           string     `json:"code"`
+	TradeDate      string     `json:"trade_date"`
+	TradeTime      string     `json:"trade_time"`
+	TradeTimestamp int64      `json:"trade_timestamp"`
+	Change         ChangeType `json:"change"`
+	BestAskPrice   Decimal    `json:"best_ask_price"`
+	BestAskSize    Decimal    `json:"best_ask_size"`
+	BestBidPrice   Decimal    `json:"best_bid_price"`
+	BestBidSize    Decimal    `json:"best_bid_size"`
+	StreamType     string     `json:"stream_type"`
+}
+
+// OrderbookEvent is a real-time orderbook message.
+type OrderbookEvent struct {
+	Orderbook
+	Code       string `json:"code"`
+	StreamType string `json:"stream_type"`
+}
+
+// MyOrderEvent is a state change of one of the user's orders, such as a
+// placement, fill or cancellation.
+type MyOrderEvent struct {
+	Type            StreamType `json:"type"`
+	Code            string     `json:"code"`
+	UUID            string     `json:"uuid"`
+	AskBid          AskBid     `json:"ask_bid"`
+	OrderType       string     `json:"order_type"`
+	State           string     `json:"state"` // wait, watch, trade, done, cancel or prevented
+	TradeUUID       string     `json:"trade_uuid,omitempty"`
+	Price           Decimal    `json:"price"`
+	AvgPrice        Decimal    `json:"avg_price"`
+	Volume          Decimal    `json:"volume"`
+	RemainingVolume Decimal    `json:"remaining_volume"`
+	ExecutedVolume  Decimal    `json:"executed_volume"`
+	TradesCount     int        `json:"trades_count"`
+	ReservedFee     Decimal    `json:"reserved_fee"`
+	RemainingFee    Decimal    `json:"remaining_fee"`
+	PaidFee         Decimal    `json:"paid_fee"`
+	Locked          Decimal    `json:"locked"`
+	ExecutedFunds   Decimal    `json:"executed_funds"`
+	TimeInForce     string     `json:"time_in_force,omitempty"`
+	TradeFee        Decimal    `json:"trade_fee,omitempty"`
+	IsMaker         bool       `json:"is_maker,omitempty"`
+	Identifier      string     `json:"identifier,omitempty"`
+	TradeTimestamp  int64      `json:"trade_timestamp,omitempty"`
+	OrderTimestamp  int64      `json:"order_timestamp"`
+	Timestamp       int64      `json:"timestamp"`
+	StreamType      string     `json:"stream_type"`
+}
+
+// MyAssetEvent is a change of the user's balances.
+type MyAssetEvent struct {
+	Type           StreamType     `json:"type"`
+	AssetUUID      string         `json:"asset_uuid"`
+	Assets         []AssetBalance `json:"assets"`
+	AssetTimestamp int64          `json:"asset_timestamp"`
+	Timestamp      int64          `json:"timestamp"`
+	StreamType     string         `json:"stream_type"`
+}
+
+// AssetBalance is the balance of a single currency in a MyAssetEvent.
+type AssetBalance struct {
+	Currency string  `json:"currency"`
+	Balance  Decimal `json:"balance"`
+	Locked   Decimal `json:"locked"`
+}
+
+func (*TickerEvent) isEvent()    {}
+func (*TradeEvent) isEvent()     {}
+func (*OrderbookEvent) isEvent() {}
+func (*MyOrderEvent) isEvent()   {}
+func (*MyAssetEvent) isEvent()   {}
+
+// Stream is a WebSocket connection to Upbit's streaming API. Dropped
+// connections are detected with ping/pong keep-alives and re-established
+// automatically; each reconnection is reported with a GapEvent.
+type Stream struct {
+	url    string
+	header func() (http.Header, error)
+	dialer *websocket.Dialer
+
+	reconnect    ReconnectPolicy
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	conn      *websocket.Conn
+	subs      []Subscription
+	positions map[string]StreamPosition
+	events    chan Event
+	err       error
+	started   bool
+	closed    bool
+
+	closeEvents func()
+}
+
+// ReconnectPolicy controls how a Stream re-establishes dropped connections.
+type ReconnectPolicy struct {
+	MaxAttempts int           // Dial attempts per outage; 0 means unlimited, negative disables reconnection
+	BaseDelay   time.Duration // Delay before the first attempt, doubled on each subsequent attempt
+	MaxDelay    time.Duration // Upper bound on a single backoff delay
+}
+
+// DefaultReconnectPolicy returns the policy used by new streams.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  30 * time.Second,
+	}
+}
+
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultPongTimeout  = 10 * time.Second
+)
+
+// StreamPosition is the last data seen for a market before a disconnect.
+type StreamPosition struct {
+	SequentialID   int64 // Sequential ID of the last trade (0 if no trade was seen)
+	TradeTimestamp int64 // Timestamp of the last trade in milliseconds
+	Timestamp      int64 // Timestamp of the last message of any type in milliseconds
+}
+
+// GapEvent reports that the connection was lost and re-established. Messages
+// published between DisconnectedAt and ReconnectedAt were missed; trades can
+// be backfilled with TradesSince.
+type GapEvent struct {
+	Cause          error                     // Error that dropped the connection
+	DisconnectedAt time.Time                 // When the connection was lost
+	ReconnectedAt  time.Time                 // When subscriptions were replayed
+	Positions      map[string]StreamPosition // Last seen data keyed by market code
+}
+
+func (*GapEvent) isEvent() {}
+
+// NewStream creates a stream for public market data. Call Subscribe and
+// Connect to start receiving events.
+func (c *Client) NewStream() *Stream {
+	var header func() (http.Header, error)
+	if c.configErr != nil {
+		header = func() (http.Header, error) { return nil, c.configErr }
+	}
+	return newStream(c.wsURL, header)
+}
+
+// NewPrivateStream creates an authenticated stream for the myOrder and
+// myAsset data types. A fresh token is signed for every connection.
+func (c *Client) NewPrivateStream() *Stream {
+	return newStream(c.wsURL+"/private", func() (http.Header, error) {
+		if c.configErr != nil {
+			return nil, c.configErr
+		}
+		token, err := c.generateToken(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token: %w", err)
+		}
+		header := http.Header{}
+		header.Set("Authorization", "Bearer "+token)
+		return header, nil
+	})
+}
+
+func newStream(url string, header func() (http.Header, error)) *Stream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Stream{
+		url:          url,
+		header:       header,
+		dialer:       websocket.DefaultDialer,
+		reconnect:    DefaultReconnectPolicy(),
+		pingInterval: defaultPingInterval,
+		pongTimeout:  defaultPongTimeout,
+		ctx:          ctx,
+		cancel:       cancel,
+		positions:    make(map[string]StreamPosition),
+		events:       make(chan Event, streamBufferSize),
+	}
+	s.closeEvents = sync.OnceFunc(func() { close(s.events) })
+	return s
+}
+
+// SetReconnectPolicy sets how dropped connections are re-established.
+// It must be called before Connect.
+func (s *Stream) SetReconnectPolicy(policy ReconnectPolicy) {
+	s.reconnect = policy
+}
+
+// SetKeepAlive sets how often pings are sent and how long to wait for any
+// message or pong before the connection is considered dead. A zero
+// pingInterval disables keep-alives. It must be called before Connect.
+func (s *Stream) SetKeepAlive(pingInterval, pongTimeout time.Duration) {
+	s.pingInterval = pingInterval
+	s.pongTimeout = pongTimeout
+}
+
+// Events returns the channel on which events are delivered. It is closed
+// when the stream terminates; Err then reports the cause.
+func (s *Stream) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns the error that terminated the stream, if any.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Subscribe adds subscriptions. Codes of an existing subscription of the same
+// type are merged. If the stream is connected, the full subscription set is
+// sent again, replacing the previous request on the server.
+func (s *Stream) Subscribe(subs ...Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+	for _, sub := range subs {
+		s.addLocked(sub)
+	}
+	if s.conn == nil {
+		return nil
+	}
+	return s.sendSubscriptionsLocked()
+}
+
+func (s *Stream) addLocked(sub Subscription) {
+	codes := make([]string, len(sub.Codes))
+	for i, code := range sub.Codes {
+		codes[i] = strings.ToUpper(code)
+	}
+	for i, existing := range s.subs {
+		if existing.Type == sub.Type {
+			for _, code := range codes {
+				if !slices.Contains(existing.Codes, code) {
+					existing.Codes = append(existing.Codes, code)
+				}
+			}
+			existing.IsOnlySnapshot = sub.IsOnlySnapshot
+			existing.IsOnlyRealtime = sub.IsOnlyRealtime
+			s.subs[i] = existing
+			return
+		}
+	}
+	sub.Codes = codes
+	s.subs = append(s.subs, sub)
+}
+
+// sendSubscriptionsLocked writes the subscription request frame.
+func (s *Stream) sendSubscriptionsLocked() error {
+	if len(s.subs) == 0 {
+		return nil
+	}
+	frame := []map[string]any{{"ticket": uuid.New().String()}}
+	for _, sub := range s.subs {
+		field := map[string]any{"type": sub.Type}
+		if len(sub.Codes) > 0 {
+			field["codes"] = sub.Codes
+		}
+		if sub.IsOnlySnapshot {
+			field["is_only_snapshot"] = true
+		}
+		if sub.IsOnlyRealtime {
+			field["is_only_realtime"] = true
+		}
+		frame = append(frame, field)
+	}
+	frame = append(frame, map[string]any{"format": "DEFAULT"})
+	return s.conn.WriteJSON(frame)
+}
+
+// Connect dials the WebSocket endpoint, sends the current subscriptions and
+// starts delivering events. ctx bounds the initial dial only; later
+// reconnections continue until Close is called.
+func (s *Stream) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrStreamClosed
+	}
+	if s.started {
+		s.mu.Unlock()
+		return errors.New("upbit: stream already connected")
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		s.mu.Lock()
+		s.started = false
+		s.mu.Unlock()
+		return err
+	}
+
+	go s.run(conn)
+	return nil
+}
+
+// dial opens a connection and replays the current subscriptions on it.
+func (s *Stream) dial(ctx context.Context) (*websocket.Conn, error) {
+	var header http.Header
+	if s.header != nil {
+		h, err := s.header()
+		if err != nil {
+			return nil, err
+		}
+		header = h
+	}
+
+	conn, _, err := s.dialer.DialContext(ctx, s.url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect stream: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		conn.Close()
+		return nil, ErrStreamClosed
+	}
+	s.conn = conn
+	if err := s.sendSubscriptionsLocked(); err != nil {
+		conn.Close()
+		s.conn = nil
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return conn, nil
+}
+
+// Close closes the connection and terminates the stream.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	started := s.started
+	conn := s.conn
+	s.mu.Unlock()
+
+	s.cancel()
+	if !started {
+		s.closeEvents()
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// run serves connections, reconnecting after failures, until the stream
+// is closed or cannot recover.
+func (s *Stream) run(conn *websocket.Conn) {
+	defer s.closeEvents()
+
+	for {
+		cause := s.serve(conn)
+		conn.Close()
+
+		s.mu.Lock()
+		s.conn = nil
+		positions := maps.Clone(s.positions)
+		s.mu.Unlock()
+
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		var apiErr *APIError
+		if errors.As(cause, &apiErr) || s.reconnect.MaxAttempts < 0 {
+			s.terminate(cause)
+			return
+		}
+
+		disconnectedAt := time.Now()
+		var err error
+		conn, err = s.redial()
+		if err != nil {
+			s.terminate(err)
+			return
+		}
+
+		gap := &GapEvent{
+			Cause:          cause,
+			DisconnectedAt: disconnectedAt,
+			ReconnectedAt:  time.Now(),
+			Positions:      positions,
+		}
+		if !s.emit(gap) {
+			conn.Close()
+			return
+		}
+	}
+}
+
+// redial dials with backoff until it succeeds, the policy is exhausted or
+// the stream is closed.
+func (s *Stream) redial() (*websocket.Conn, error) {
+	backoff := RetryPolicy{BaseDelay: s.reconnect.BaseDelay, MaxDelay: s.reconnect.MaxDelay}
+	for attempt := 1; ; attempt++ {
+		if err := sleepContext(s.ctx, backoff.backoff(attempt, 0)); err != nil {
+			return nil, err
+		}
+		conn, err := s.dial(s.ctx)
+		if err == nil {
+			return conn, nil
+		}
+		if s.ctx.Err() != nil || (s.reconnect.MaxAttempts > 0 && attempt >= s.reconnect.MaxAttempts) {
+			return nil, err
+		}
+	}
+}
+
+// terminate records err as the stream's final error.
+func (s *Stream) terminate(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.err = err
+		s.closed = true
+	}
+	s.cancel()
+}
+
+// emit delivers ev, reporting false if the stream was closed first.
+func (s *Stream) emit(ev Event) bool {
+	select {
+	case s.events <- ev:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// serve reads from conn until it fails, keeping it alive with pings.
+func (s *Stream) serve(conn *websocket.Conn) error {
+	keepAlive := s.pingInterval > 0
+	deadline := func() time.Time {
+		if !keepAlive {
+			return time.Time{}
+		}
+		return time.Now().Add(s.pingInterval + s.pongTimeout)
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(deadline())
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if keepAlive {
+		go s.ping(conn, stop)
+	}
+
+	for {
+		// The deadline is set only while reading, so time spent waiting
+		// for a slow consumer in emit does not count against the peer.
+		conn.SetReadDeadline(deadline())
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		ev, err := decodeEvent(data)
+		if err != nil {
+			return err
+		}
+		if ev == nil {
+			continue
+		}
+		s.track(ev)
+		if !s.emit(ev) {
+			return ErrStreamClosed
+		}
+	}
+}
+
+// ping sends keep-alive pings on conn until stop is closed.
+func (s *Stream) ping(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.pongTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// track records the last seen position of the event's market.
+func (s *Stream) track(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *TradeEvent:
+		pos := s.positions[ev.Code]
+		pos.SequentialID = ev.SequentialID
+		pos.TradeTimestamp = ev.TradeTimestamp
+		pos.Timestamp = max(pos.Timestamp, ev.Timestamp)
+		s.positions[ev.Code] = pos
+	case *TickerEvent:
+		pos := s.positions[ev.Code]
+		pos.Timestamp = max(pos.Timestamp, ev.Timestamp)
+		s.positions[ev.Code] = pos
+	case *OrderbookEvent:
+		pos := s.positions[ev.Code]
+		pos.Timestamp = max(pos.Timestamp, ev.Timestamp)
+		s.positions[ev.Code] = pos
+	}
+}
+
+// decodeEvent decodes a WebSocket message. It returns a nil event for
+// messages that carry no data, such as status replies.
+func decodeEvent(data []byte) (Event, error) {
+	var envelope struct {
+		Type  StreamType   `json:"type"`
+		Error *ErrorDetail `json:"error"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode stream message: %w", err)
+	}
+	if envelope.Error != nil {
+		return nil, &APIError{Err: *envelope.Error}
+	}
+
+	switch envelope.Type {
+	case StreamTypeTicker:
+		var ev TickerEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		ev.Market = ev.Code
+		return &ev, nil
+	case StreamTypeTrade:
+		var ev TradeEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		ev.Market = ev.Code
+		ev.TradeDateUtc = ev.TradeDate
+		ev.TradeTimeUtc = ev.TradeTime
+		return &ev, nil
+	case StreamTypeOrderbook:
+		var ev OrderbookEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		ev.Market = ev.Code
+		return &ev, nil
+	case StreamTypeMyOrder:
+		var ev MyOrderEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		return &ev, nil
+	case StreamTypeMyAsset:
+		var ev MyAssetEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		return &ev, nil
+	default:
+		return nil, nil
+	}
+}
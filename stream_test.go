@@ -0,0 +1,405 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+// newStreamServer starts a WebSocket stand-in that passes each connection's
+// subscription frame to handle and then lets handle write messages.
+func newStreamServer(t *testing.T, handle func(conn *websocket.Conn, frame []map[string]any)) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var frame []map[string]any
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		handle(conn, frame)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func nextEvent(t *testing.T, stream *Stream) Event {
+	t.Helper()
+	select {
+	case ev, ok := <-stream.Events():
+		if !ok {
+			t.Fatalf("Stream terminated: %v", stream.Err())
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	return nil
+}
+
+func TestStreamPublicEvents(t *testing.T) {
+	server := newStreamServer(t, func(conn *websocket.Conn, frame []map[string]any) {
+		if len(frame) != 4 {
+			t.Errorf("Expected 4 frame fields, got %d", len(frame))
+			return
+		}
+		if frame[0]["ticket"] == "" {
+			t.Error("Expected ticket field")
+		}
+		if frame[1]["type"] != "ticker" {
+			t.Errorf("Expected ticker subscription, got %v", frame[1]["type"])
+		}
+		codes, _ := frame[1]["codes"].([]any)
+		if len(codes) != 2 || codes[0] != "KRW-BTC" || codes[1] != "KRW-ETH" {
+			t.Errorf("Unexpected codes %v", frame[1]["codes"])
+		}
+
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"ticker","code":"KRW-BTC","trade_price":50000000,"change":"RISE","stream_type":"REALTIME"}`))
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"trade","code":"KRW-BTC","trade_price":50000000,"trade_volume":0.1,"ask_bid":"BID","trade_date":"2024-01-01","trade_time":"00:00:01","sequential_id":17}`))
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"orderbook","code":"KRW-BTC","total_ask_size":1.5,"total_bid_size":2.5,"orderbook_units":[{"ask_price":50100000,"bid_price":50000000,"ask_size":1.5,"bid_size":2.5}]}`))
+		conn.ReadMessage()
+	})
+
+	client := NewClient("", "")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewStream()
+	defer stream.Close()
+	stream.Subscribe(Subscription{Type: StreamTypeTicker, Codes: []string{"KRW-BTC"}})
+	stream.Subscribe(Subscription{Type: StreamTypeTicker, Codes: []string{"krw-eth"}})
+	stream.Subscribe(Subscription{Type: StreamTypeTrade, Codes: []string{"KRW-BTC"}})
+
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ticker, ok := nextEvent(t, stream).(*TickerEvent)
+	if !ok {
+		t.Fatal("Expected *TickerEvent")
+	}
+	if ticker.Market != "KRW-BTC" || ticker.TradePrice.String() != "50000000" {
+		t.Errorf("Unexpected ticker: %+v", ticker.Ticker)
+	}
+
+	trade, ok := nextEvent(t, stream).(*TradeEvent)
+	if !ok {
+		t.Fatal("Expected *TradeEvent")
+	}
+	if trade.SequentialID != 17 || trade.TradeDateUtc != "2024-01-01" {
+		t.Errorf("Unexpected trade: %+v", trade.Trade)
+	}
+
+	book, ok := nextEvent(t, stream).(*OrderbookEvent)
+	if !ok {
+		t.Fatal("Expected *OrderbookEvent")
+	}
+	if book.Market != "KRW-BTC" || len(book.OrderbookUnits) != 1 {
+		t.Errorf("Unexpected orderbook: %+v", book.Orderbook)
+	}
+}
+
+func TestStreamServerError(t *testing.T) {
+	server := newStreamServer(t, func(conn *websocket.Conn, frame []map[string]any) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"error":{"name":"WRONG_FORMAT","message":"bad request"}}`))
+		conn.ReadMessage()
+	})
+
+	client := NewClient("", "")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewStream()
+	defer stream.Close()
+	stream.Subscribe(Subscription{Type: StreamTypeTicker, Codes: []string{"KRW-BTC"}})
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for range stream.Events() {
+	}
+	apiErr, ok := stream.Err().(*APIError)
+	if !ok {
+		t.Fatalf("Expected APIError, got %v", stream.Err())
+	}
+	if apiErr.Err.Name != "WRONG_FORMAT" {
+		t.Errorf("Expected error name 'WRONG_FORMAT', got '%s'", apiErr.Err.Name)
+	}
+}
+
+func TestDecodeEventIgnoresStatus(t *testing.T) {
+	ev, err := decodeEvent([]byte(`{"status":"UP"}`))
+	if err != nil || ev != nil {
+		t.Errorf("Expected status message to be ignored, got %v, %v", ev, err)
+	}
+
+	data, _ := json.Marshal(map[string]any{"type": "ticker", "code": "KRW-ETH"})
+	ev, err = decodeEvent(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ev.(*TickerEvent).Market != "KRW-ETH" {
+		t.Errorf("Expected market to be taken from code")
+	}
+}
+
+func TestPrivateStreamEvents(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/private" {
+			t.Errorf("Expected path '/private', got '%s'", r.URL.Path)
+		}
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, err := jwt.Parse(auth, func(*jwt.Token) (any, error) { return []byte("secret"), nil })
+		if err != nil || !token.Valid {
+			t.Errorf("Expected valid token, got %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if claims := token.Claims.(jwt.MapClaims); claims["access_key"] != "access" {
+			t.Errorf("Expected access_key 'access', got %v", claims["access_key"])
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var frame []map[string]any
+		conn.ReadJSON(&frame)
+		if len(frame) != 4 || frame[1]["type"] != "myOrder" || frame[2]["type"] != "myAsset" {
+			t.Errorf("Unexpected subscription frame %v", frame)
+		}
+
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"myOrder","code":"KRW-BTC","uuid":"order-1","ask_bid":"BID","order_type":"limit","state":"trade","trade_uuid":"trade-1","price":50000000,"volume":0.1,"executed_volume":0.05,"remaining_volume":0.05,"is_maker":true}`))
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"myAsset","asset_uuid":"asset-1","assets":[{"currency":"KRW","balance":1000.5,"locked":200}]}`))
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewPrivateStream()
+	defer stream.Close()
+	stream.Subscribe(
+		Subscription{Type: StreamTypeMyOrder},
+		Subscription{Type: StreamTypeMyAsset},
+	)
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	order, ok := nextEvent(t, stream).(*MyOrderEvent)
+	if !ok {
+		t.Fatal("Expected *MyOrderEvent")
+	}
+	if order.UUID != "order-1" || order.State != "trade" || order.ExecutedVolume.String() != "0.05" || !order.IsMaker {
+		t.Errorf("Unexpected order event: %+v", order)
+	}
+
+	asset, ok := nextEvent(t, stream).(*MyAssetEvent)
+	if !ok {
+		t.Fatal("Expected *MyAssetEvent")
+	}
+	if len(asset.Assets) != 1 || asset.Assets[0].Currency != "KRW" || asset.Assets[0].Locked.String() != "200" {
+		t.Errorf("Unexpected asset event: %+v", asset)
+	}
+}
+
+func TestStreamReconnectsAndReportsGap(t *testing.T) {
+	var conns atomic.Int32
+	server := newStreamServer(t, func(conn *websocket.Conn, frame []map[string]any) {
+		if len(frame) != 3 || frame[1]["type"] != "trade" {
+			t.Errorf("Expected trade subscription to be replayed, got %v", frame)
+		}
+		switch conns.Add(1) {
+		case 1:
+			conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"trade","code":"KRW-BTC","sequential_id":1,"trade_timestamp":1000,"timestamp":1001}`))
+			// Drop the connection.
+		default:
+			conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"trade","code":"KRW-BTC","sequential_id":5,"trade_timestamp":5000,"timestamp":5001}`))
+			conn.ReadMessage()
+		}
+	})
+
+	client := NewClient("", "")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewStream()
+	defer stream.Close()
+	stream.SetReconnectPolicy(ReconnectPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	stream.Subscribe(Subscription{Type: StreamTypeTrade, Codes: []string{"KRW-BTC"}})
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if trade, ok := nextEvent(t, stream).(*TradeEvent); !ok || trade.SequentialID != 1 {
+		t.Fatalf("Expected first trade, got %#v", trade)
+	}
+
+	gap, ok := nextEvent(t, stream).(*GapEvent)
+	if !ok {
+		t.Fatal("Expected *GapEvent")
+	}
+	pos := gap.Positions["KRW-BTC"]
+	if pos.SequentialID != 1 || pos.TradeTimestamp != 1000 || pos.Timestamp != 1001 {
+		t.Errorf("Unexpected position: %+v", pos)
+	}
+	if gap.ReconnectedAt.Before(gap.DisconnectedAt) {
+		t.Error("Expected ReconnectedAt after DisconnectedAt")
+	}
+
+	if trade, ok := nextEvent(t, stream).(*TradeEvent); !ok || trade.SequentialID != 5 {
+		t.Fatalf("Expected trade after reconnect, got %#v", trade)
+	}
+}
+
+func TestStreamDetectsDeadConnection(t *testing.T) {
+	var conns atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+
+	server := newStreamServer(t, func(conn *websocket.Conn, frame []map[string]any) {
+		if conns.Add(1) == 1 {
+			// Stop reading so pings are never answered.
+			<-release
+			return
+		}
+		conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"ticker","code":"KRW-BTC"}`))
+		conn.ReadMessage()
+	})
+
+	client := NewClient("", "")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewStream()
+	defer stream.Close()
+	stream.SetKeepAlive(20*time.Millisecond, 20*time.Millisecond)
+	stream.SetReconnectPolicy(ReconnectPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	stream.Subscribe(Subscription{Type: StreamTypeTicker, Codes: []string{"KRW-BTC"}})
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := nextEvent(t, stream).(*GapEvent); !ok {
+		t.Fatal("Expected *GapEvent after keep-alive timeout")
+	}
+	if _, ok := nextEvent(t, stream).(*TickerEvent); !ok {
+		t.Fatal("Expected *TickerEvent after reconnect")
+	}
+}
+
+func TestStreamSlowConsumer(t *testing.T) {
+	var conns atomic.Int32
+	const n = streamBufferSize + 2
+	server := newStreamServer(t, func(conn *websocket.Conn, frame []map[string]any) {
+		conns.Add(1)
+		// Keep reading so that pings are answered.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		for i := range n {
+			if i == n-1 {
+				// Send the last message while the client is blocked on
+				// the consumer.
+				time.Sleep(100 * time.Millisecond)
+			}
+			conn.WriteMessage(websocket.BinaryMessage, []byte(`{"type":"ticker","code":"KRW-BTC","timestamp":`+strconv.Itoa(i)+`}`))
+		}
+		<-done
+	})
+
+	client := NewClient("", "")
+	client.SetWebSocketURL(wsURL(server))
+
+	stream := client.NewStream()
+	defer stream.Close()
+	stream.SetKeepAlive(20*time.Millisecond, 20*time.Millisecond)
+	stream.SetReconnectPolicy(ReconnectPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	stream.Subscribe(Subscription{Type: StreamTypeTicker, Codes: []string{"KRW-BTC"}})
+	if err := stream.Connect(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A full buffer blocks the reader well past the keep-alive deadline.
+	time.Sleep(200 * time.Millisecond)
+	for i := range n {
+		if _, ok := nextEvent(t, stream).(*TickerEvent); !ok {
+			t.Fatalf("Expected *TickerEvent %d without reconnecting", i)
+		}
+	}
+	if conns.Load() != 1 {
+		t.Errorf("Expected 1 connection, got %d", conns.Load())
+	}
+}
+
+func TestTradesSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Trades 1..600 happened yesterday and 601..1200 today; pages are
+		// newest first.
+		newest, oldest := int64(1200), int64(601)
+		switch r.URL.Query().Get("days_ago") {
+		case "":
+		case "1":
+			newest, oldest = 600, 1
+		default:
+			w.Write([]byte(`[]`))
+			return
+		}
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			newest, _ = strconv.ParseInt(cursor, 10, 64)
+			newest--
+		}
+		var trades []Trade
+		for id := newest; id > newest-maxTradesCount && id >= oldest; id-- {
+			trades = append(trades, Trade{Market: "KRW-BTC", SequentialID: id})
+		}
+		json.NewEncoder(w).Encode(trades)
+	}))
+	defer server.Close()
+
+	client := NewClient("", "")
+	client.SetBaseURL(server.URL + "/v1")
+
+	trades, err := client.TradesSince("KRW-BTC", 300)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(trades) != 900 {
+		t.Fatalf("Expected 900 trades, got %d", len(trades))
+	}
+	if trades[0].SequentialID != 301 || trades[len(trades)-1].SequentialID != 1200 {
+		t.Errorf("Expected trades 301..1200 oldest first, got %d..%d",
+			trades[0].SequentialID, trades[len(trades)-1].SequentialID)
+	}
+
+	// Trade 0 is older than any day served.
+	if _, err := client.TradesSince("KRW-BTC", 0); err == nil {
+		t.Error("Expected error for a trade older than seven days")
+	}
+}
@@ -0,0 +1,282 @@
+// Package upbitmetrics collects request metrics from an upbit.Client and
+// exposes them in the Prometheus text exposition format, without depending
+// on a Prometheus client library.
+//
+//	metrics := upbitmetrics.New()
+//	client := upbit.NewClient(accessKey, secretKey, upbit.WithHooks(metrics))
+//
+//	http.Handle("/metrics", metrics)
+//	go http.ListenAndServe("localhost:9100", nil)
+//
+// The following metrics are exported:
+//
+//	upbit_requests_total{method,endpoint,code}            counter
+//	upbit_request_duration_seconds{method,endpoint}       histogram
+//	upbit_request_errors_total{method,endpoint,error}     counter
+//	upbit_rate_limit_remaining{group,window}              gauge
+//
+// The error label is the Upbit error name for API errors, or one of
+// "network", "canceled", "deadline_exceeded" and "other".
+package upbitmetrics
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the request duration
+// histogram buckets used by New.
+var DefaultBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// endpointKey identifies an API endpoint.
+type endpointKey struct {
+	method, endpoint string
+}
+
+// statusKey identifies an endpoint and response status.
+type statusKey struct {
+	endpointKey
+	code int
+}
+
+// errorKey identifies an endpoint and error name.
+type errorKey struct {
+	endpointKey
+	name string
+}
+
+// remainingKey identifies a rate limit window.
+type remainingKey struct {
+	group  upbit.RateLimitGroup
+	window string
+}
+
+// histogram is a cumulative Prometheus histogram.
+type histogram struct {
+	counts []uint64 // Observations per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Metrics is an upbit.Hook that aggregates request metrics. It also
+// implements http.Handler, serving the metrics in the Prometheus text
+// format.
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[statusKey]uint64
+	durations map[endpointKey]*histogram
+	errors    map[errorKey]uint64
+	remaining map[remainingKey]int
+}
+
+// New creates a Metrics using DefaultBuckets.
+func New() *Metrics {
+	return NewWithBuckets(DefaultBuckets)
+}
+
+// NewWithBuckets creates a Metrics whose duration histogram uses the given
+// bucket upper bounds in seconds.
+func NewWithBuckets(buckets []float64) *Metrics {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Metrics{
+		buckets:   slices.Compact(buckets),
+		requests:  make(map[statusKey]uint64),
+		durations: make(map[endpointKey]*histogram),
+		errors:    make(map[errorKey]uint64),
+		remaining: make(map[remainingKey]int),
+	}
+}
+
+// RequestStart implements upbit.Hook.
+func (m *Metrics) RequestStart(ctx context.Context, info upbit.RequestInfo) context.Context {
+	return ctx
+}
+
+// RequestError implements upbit.Hook.
+func (m *Metrics) RequestError(ctx context.Context, info upbit.RequestInfo, err error) {
+	key := errorKey{endpointKey{info.Method, info.Endpoint}, errorName(err)}
+	m.mu.Lock()
+	m.errors[key]++
+	m.mu.Unlock()
+}
+
+// RequestEnd implements upbit.Hook.
+func (m *Metrics) RequestEnd(ctx context.Context, info upbit.RequestInfo, result upbit.RequestResult) {
+	ep := endpointKey{info.Method, info.Endpoint}
+	seconds := result.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[statusKey{ep, result.StatusCode}]++
+
+	h := m.durations[ep]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[ep] = h
+	}
+	if i, _ := slices.BinarySearch(m.buckets, seconds); i < len(m.buckets) {
+		h.counts[i]++
+	}
+	h.sum += seconds
+	h.count++
+
+	if result.HasRemaining {
+		r := result.Remaining
+		m.remaining[remainingKey{r.Group, "sec"}] = r.Sec
+		if r.Min >= 0 {
+			m.remaining[remainingKey{r.Group, "min"}] = r.Min
+		}
+	}
+}
+
+// errorName returns the error label for err.
+func errorName(err error) string {
+	var apiErr *upbit.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Err.Name
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case upbit.IsRetryable(err):
+		return "network"
+	}
+	return "other"
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	m.mu.Lock()
+	m.writeRequests(cw)
+	m.writeDurations(cw)
+	m.writeErrors(cw)
+	m.writeRemaining(cw)
+	m.mu.Unlock()
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (m *Metrics) writeRequests(w *countingWriter) {
+	w.header("upbit_requests_total", "counter", "API calls by endpoint and final HTTP status (0 if no response was received).")
+	for _, k := range sortedKeys(m.requests, func(a, b statusKey) int {
+		return cmp.Or(compareEndpoints(a.endpointKey, b.endpointKey), cmp.Compare(a.code, b.code))
+	}) {
+		w.sample("upbit_requests_total", labels("method", k.method, "endpoint", k.endpoint, "code", strconv.Itoa(k.code)), float64(m.requests[k]))
+	}
+}
+
+func (m *Metrics) writeDurations(w *countingWriter) {
+	w.header("upbit_request_duration_seconds", "histogram", "API call latency including retries.")
+	for _, k := range sortedKeys(m.durations, compareEndpoints) {
+		h := m.durations[k]
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += h.counts[i]
+			w.sample("upbit_request_duration_seconds_bucket", labels("method", k.method, "endpoint", k.endpoint, "le", formatFloat(upper)), float64(cumulative))
+		}
+		w.sample("upbit_request_duration_seconds_bucket", labels("method", k.method, "endpoint", k.endpoint, "le", "+Inf"), float64(h.count))
+		w.sample("upbit_request_duration_seconds_sum", labels("method", k.method, "endpoint", k.endpoint), h.sum)
+		w.sample("upbit_request_duration_seconds_count", labels("method", k.method, "endpoint", k.endpoint), float64(h.count))
+	}
+}
+
+func (m *Metrics) writeErrors(w *countingWriter) {
+	w.header("upbit_request_errors_total", "counter", "Failed API calls by endpoint and error name.")
+	for _, k := range sortedKeys(m.errors, func(a, b errorKey) int {
+		return cmp.Or(compareEndpoints(a.endpointKey, b.endpointKey), strings.Compare(a.name, b.name))
+	}) {
+		w.sample("upbit_request_errors_total", labels("method", k.method, "endpoint", k.endpoint, "error", k.name), float64(m.errors[k]))
+	}
+}
+
+func (m *Metrics) writeRemaining(w *countingWriter) {
+	w.header("upbit_rate_limit_remaining", "gauge", "Requests remaining in the current window as last reported by Upbit.")
+	for _, k := range sortedKeys(m.remaining, func(a, b remainingKey) int {
+		return cmp.Or(strings.Compare(string(a.group), string(b.group)), strings.Compare(a.window, b.window))
+	}) {
+		w.sample("upbit_rate_limit_remaining", labels("group", string(k.group), "window", k.window), float64(m.remaining[k]))
+	}
+}
+
+func compareEndpoints(a, b endpointKey) int {
+	return cmp.Or(strings.Compare(a.endpoint, b.endpoint), strings.Compare(a.method, b.method))
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, compare func(a, b K) int) []K {
+	return slices.SortedFunc(maps.Keys(m), compare)
+}
+
+// labels formats name/value pairs as a Prometheus label set.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter writes metric lines, remembering the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *countingWriter) header(name, typ, help string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (w *countingWriter) sample(name, labels string, value float64) {
+	w.printf("%s%s %s\n", name, labels, formatFloat(value))
+}
@@ -0,0 +1,112 @@
+package upbitmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/ticker":
+			w.Header().Set("Remaining-Req", "group=ticker; min=599; sec=9")
+			json.NewEncoder(w).Encode([]upbit.Ticker{{Market: "KRW-BTC"}})
+		case "/v1/orders":
+			w.Header().Set("Remaining-Req", "group=order; sec=7")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"name":"insufficient_funds_bid","message":"not enough KRW"}}`))
+		}
+	}))
+	defer server.Close()
+
+	metrics := NewWithBuckets([]float64{1, 0.5})
+	client := upbit.NewClient("access", "secret",
+		upbit.WithBaseURL(server.URL+"/v1"),
+		upbit.WithHooks(metrics),
+	)
+
+	for range 2 {
+		if _, err := client.GetTicker([]string{"KRW-BTC"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypePrice,
+		Price:   upbit.DecimalFromInt(10000),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.GetTickerContext(ctx, []string{"KRW-BTC"})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %s", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+
+	for _, line := range []string{
+		"# TYPE upbit_requests_total counter",
+		`upbit_requests_total{method="GET",endpoint="/ticker",code="0"} 1`,
+		`upbit_requests_total{method="GET",endpoint="/ticker",code="200"} 2`,
+		`upbit_requests_total{method="POST",endpoint="/orders",code="400"} 1`,
+		"# TYPE upbit_request_duration_seconds histogram",
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="0.5"} 3`,
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="1"} 3`,
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="+Inf"} 3`,
+		`upbit_request_duration_seconds_count{method="POST",endpoint="/orders"} 1`,
+		`upbit_request_errors_total{method="GET",endpoint="/ticker",error="canceled"} 1`,
+		`upbit_request_errors_total{method="POST",endpoint="/orders",error="insufficient_funds_bid"} 1`,
+		`upbit_rate_limit_remaining{group="order",window="sec"} 7`,
+		`upbit_rate_limit_remaining{group="ticker",window="min"} 599`,
+		`upbit_rate_limit_remaining{group="ticker",window="sec"} 9`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `group="order",window="min"`) {
+		t.Error("Expected no minute gauge when Upbit does not report one")
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	metrics := NewWithBuckets([]float64{0.1, 1})
+	info := upbit.RequestInfo{Method: http.MethodGet, Endpoint: "/ticker"}
+	for _, d := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, 3 * time.Second} {
+		metrics.RequestEnd(context.Background(), info, upbit.RequestResult{StatusCode: 200, Duration: d})
+	}
+
+	var b strings.Builder
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, line := range []string{
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="0.1"} 2`,
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="1"} 3`,
+		`upbit_request_duration_seconds_bucket{method="GET",endpoint="/ticker",le="+Inf"} 4`,
+		`upbit_request_duration_seconds_sum{method="GET",endpoint="/ticker"} 3.65`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, b.String())
+		}
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	got := labels("endpoint", "a\"b\\c\nd")
+	if want := `{endpoint="a\"b\\c\nd"}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
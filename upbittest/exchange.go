@@ -0,0 +1,1040 @@
+package upbittest
+
+import (
+	"cmp"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// volumePrecision is the number of decimal places of filled volumes.
+const volumePrecision = 8
+
+// orderState is an order held by the server.
+type orderState struct {
+	upbit.OrderDetail
+	identifier string
+	currency   string        // Currency the order's funds are locked in
+	perVolume  upbit.Decimal // Amount locked per unit of volume for limit bids
+	visibleAt  time.Time     // Lookups report the order missing until then
+}
+
+// fill is one execution of an order against a book level.
+type fill struct {
+	level  int
+	price  upbit.Decimal
+	volume upbit.Decimal
+}
+
+func init() {
+	handle(http.MethodGet, "/accounts", true, (*Server).getAccounts)
+	handle(http.MethodGet, "/orders/chance", true, (*Server).getOrderChance)
+	handle(http.MethodGet, "/order", true, (*Server).getOrder)
+	handle(http.MethodGet, "/orders", true, (*Server).getOrders)
+	handle(http.MethodGet, "/orders/open", true, (*Server).getOpenOrders)
+	handle(http.MethodGet, "/orders/closed", true, (*Server).getClosedOrders)
+	handle(http.MethodPost, "/orders", true, (*Server).placeOrder)
+	handle(http.MethodPost, "/orders/test", true, (*Server).testOrder)
+	handle(http.MethodDelete, "/order", true, (*Server).cancelOrder)
+	handle(http.MethodPost, "/orders/cancel_and_new", true, (*Server).replaceOrder)
+	handle(http.MethodDelete, "/orders/uuids", true, (*Server).cancelOrders)
+	handle(http.MethodDelete, "/orders/open", true, (*Server).cancelOpenOrders)
+	handle(http.MethodGet, "/withdraws", true, (*Server).getWithdraws)
+	handle(http.MethodGet, "/withdraw", true, (*Server).getWithdraw)
+	handle(http.MethodGet, "/withdraws/chance", true, (*Server).getWithdrawChance)
+	handle(http.MethodPost, "/withdraws/coin", true, (*Server).withdrawCoin)
+	handle(http.MethodPost, "/withdraws/krw", true, (*Server).withdrawKRW)
+	handle(http.MethodGet, "/deposits", true, (*Server).getDeposits)
+	handle(http.MethodGet, "/deposit", true, (*Server).getDeposit)
+	handle(http.MethodPost, "/deposits/generate_coin_address", true, (*Server).generateDepositAddress)
+	handle(http.MethodGet, "/deposits/coin_addresses", true, (*Server).getDepositAddresses)
+	handle(http.MethodGet, "/deposits/coin_address", true, (*Server).getDepositAddress)
+	handle(http.MethodGet, "/status/wallet", true, (*Server).getWalletStatus)
+	handle(http.MethodGet, "/api_keys", true, (*Server).getAPIKeys)
+}
+
+// === Accounts ===
+
+func (s *Server) getAccounts(url.Values) (any, *apiError) {
+	currencies := make([]string, 0, len(s.balances))
+	for currency := range s.balances {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	accounts := []upbit.Account{}
+	for _, currency := range currencies {
+		b := s.balances[currency]
+		if b.balance.IsZero() && b.locked.IsZero() {
+			continue
+		}
+		accounts = append(accounts, s.account(currency))
+	}
+	return accounts, nil
+}
+
+func (s *Server) account(currency string) upbit.Account {
+	b := s.balanceLocked(currency)
+	return upbit.Account{
+		Currency:     currency,
+		Balance:      b.balance,
+		Locked:       b.locked,
+		AvgBuyPrice:  b.avgBuyPrice,
+		UnitCurrency: "KRW",
+	}
+}
+
+// === Orders ===
+
+// Orders returns all orders in creation order.
+func (s *Server) Orders() []upbit.OrderDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := make([]upbit.OrderDetail, 0, len(s.orderSeq))
+	for _, id := range s.orderSeq {
+		orders = append(orders, s.orders[id].detail())
+	}
+	return orders
+}
+
+// Order returns an order by UUID.
+func (s *Server) Order(uuid string) (upbit.OrderDetail, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[uuid]
+	if !ok {
+		return upbit.OrderDetail{}, false
+	}
+	return o.detail(), true
+}
+
+func (o *orderState) visible() bool {
+	return !time.Now().Before(o.visibleAt)
+}
+
+func (o *orderState) isLimit() bool {
+	return upbit.OrderType(o.OrdType) == upbit.OrderTypeLimit
+}
+
+func (o *orderState) detail() upbit.OrderDetail {
+	d := o.OrderDetail
+	d.Trades = slices.Clone(d.Trades)
+	return d
+}
+
+// marketCurrencies splits a market code like "KRW-BTC" into its quote and
+// base currencies.
+func marketCurrencies(market string) (quote, base string) {
+	quote, base, _ = strings.Cut(market, "-")
+	return quote, base
+}
+
+func (s *Server) getOrderChance(params url.Values) (any, *apiError) {
+	market, apiErr := required(params, "market")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if _, apiErr := s.knownMarket(market); apiErr != nil {
+		return nil, apiErr
+	}
+	quote, base := marketCurrencies(market)
+
+	bidAccount := s.account(quote)
+	askAccount := s.account(base)
+	return map[string]any{
+		"bid_fee": s.fee,
+		"ask_fee": s.fee,
+		"market": map[string]any{
+			"id":          market,
+			"name":        base + "/" + quote,
+			"order_types": []string{},
+			"bid_types":   []string{"limit", "price", "best_fok", "best_ioc", "limit_ioc", "limit_fok"},
+			"ask_types":   []string{"limit", "market", "best_fok", "best_ioc", "limit_ioc", "limit_fok"},
+			"order_sides": []string{"ask", "bid"},
+			"bid":         map[string]any{"currency": quote, "min_total": s.minTotal},
+			"ask":         map[string]any{"currency": quote, "min_total": s.minTotal},
+			"max_total":   upbit.DecimalFromInt(1000000000),
+			"state":       "active",
+		},
+		"bid_account": bidAccount,
+		"ask_account": askAccount,
+	}, nil
+}
+
+// findOrder looks an order up by the "uuid" or "identifier" parameter.
+func (s *Server) findOrder(params url.Values) (*orderState, *apiError) {
+	if id := params.Get("uuid"); id != "" {
+		if o, ok := s.orders[id]; ok && o.visible() {
+			return o, nil
+		}
+	} else if identifier := params.Get("identifier"); identifier != "" {
+		for _, o := range s.orders {
+			if o.identifier == identifier && o.visible() {
+				return o, nil
+			}
+		}
+	} else {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "uuid or identifier is required")
+	}
+	return nil, errorf(http.StatusNotFound, upbit.ErrOrderNotFound, "주문을 찾지 못했습니다.")
+}
+
+func (s *Server) getOrder(params url.Values) (any, *apiError) {
+	o, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return o.detail(), nil
+}
+
+// sortOrders orders by creation time, newest first unless orderBy is "asc".
+func sortOrders(orders []upbit.Order, orderBy string) {
+	slices.SortStableFunc(orders, func(a, b upbit.Order) int {
+		if orderBy == "asc" {
+			return cmp.Compare(a.CreatedAt, b.CreatedAt)
+		}
+		return cmp.Compare(b.CreatedAt, a.CreatedAt)
+	})
+}
+
+// paginate returns the page of items selected by the "page" and "limit"
+// parameters.
+func paginate[T any](items []T, params url.Values, maxLimit int) ([]T, *apiError) {
+	limit := 100
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid limit %q", v)
+		}
+		limit = n
+	}
+	page := 1
+	if v := params.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid page %q", v)
+		}
+		page = n
+	}
+
+	start := min((page-1)*limit, len(items))
+	end := min(start+limit, len(items))
+	return items[start:end], nil
+}
+
+func (s *Server) getOrders(params url.Values) (any, *apiError) {
+	uuids := listParam(params, "uuids")
+	identifiers := listParam(params, "identifiers")
+	states := listParam(params, "states")
+	if state := params.Get("state"); state != "" {
+		states = append(states, state)
+	}
+	if len(states) == 0 && len(uuids) == 0 && len(identifiers) == 0 {
+		states = []string{string(upbit.OrderStateWait)}
+	}
+
+	orders := []upbit.Order{}
+	for _, id := range s.orderSeq {
+		o := s.orders[id]
+		switch {
+		case params.Get("market") != "" && o.Market != params.Get("market"):
+		case len(uuids) > 0 && !slices.Contains(uuids, o.UUID):
+		case len(identifiers) > 0 && !slices.Contains(identifiers, o.identifier):
+		case len(states) > 0 && !slices.Contains(states, o.State):
+		default:
+			orders = append(orders, o.Order)
+		}
+	}
+	sortOrders(orders, params.Get("order_by"))
+	return paginate(orders, params, 100)
+}
+
+func (s *Server) getOpenOrders(params url.Values) (any, *apiError) {
+	states := listParam(params, "states")
+	if state := params.Get("state"); state != "" {
+		states = append(states, state)
+	}
+	if len(states) == 0 {
+		states = []string{string(upbit.OrderStateWait)}
+	}
+	for _, state := range states {
+		if state != string(upbit.OrderStateWait) && state != string(upbit.OrderStateWatch) {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid state %q", state)
+		}
+	}
+
+	orders := []upbit.Order{}
+	for _, id := range s.orderSeq {
+		o := s.orders[id]
+		switch {
+		case params.Get("market") != "" && o.Market != params.Get("market"):
+		case !slices.Contains(states, o.State):
+		default:
+			orders = append(orders, o.Order)
+		}
+	}
+	sortOrders(orders, params.Get("order_by"))
+	return paginate(orders, params, 100)
+}
+
+// parseTime parses a time parameter given in RFC 3339 or Unix milliseconds.
+func parseTime(v string) (time.Time, bool) {
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, err == nil
+}
+
+func (s *Server) getClosedOrders(params url.Values) (any, *apiError) {
+	states := listParam(params, "states")
+	if len(states) == 0 {
+		states = []string{string(upbit.OrderStateDone), string(upbit.OrderStateCancel)}
+	}
+
+	var start, end time.Time
+	if v := params.Get("start_time"); v != "" {
+		t, ok := parseTime(v)
+		if !ok {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid start_time %q", v)
+		}
+		start = t
+	}
+	if v := params.Get("end_time"); v != "" {
+		t, ok := parseTime(v)
+		if !ok {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid end_time %q", v)
+		}
+		end = t
+	}
+	if !start.IsZero() && !end.IsZero() && end.Sub(start) > 7*24*time.Hour {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "start_time and end_time must be at most 7 days apart")
+	}
+
+	orders := []upbit.Order{}
+	for _, id := range s.orderSeq {
+		o := s.orders[id]
+		created, _ := time.Parse(time.RFC3339, o.CreatedAt)
+		switch {
+		case params.Get("market") != "" && o.Market != params.Get("market"):
+		case !slices.Contains(states, o.State):
+		case !start.IsZero() && created.Before(start):
+		case !end.IsZero() && created.After(end):
+		default:
+			orders = append(orders, o.Order)
+		}
+	}
+	sortOrders(orders, params.Get("order_by"))
+	return paginate(orders, params, 1000)
+}
+
+func (s *Server) placeOrder(params url.Values) (any, *apiError) {
+	o, apiErr := s.newOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	b := s.balanceLocked(o.currency)
+	b.balance = b.balance.Sub(o.Locked)
+	b.locked = b.locked.Add(o.Locked)
+
+	s.orders[o.UUID] = o
+	s.orderSeq = append(s.orderSeq, o.UUID)
+
+	// A post-only order that would take liquidity is cancelled unfilled.
+	if upbit.TimeInForce(o.TimeInForce) == upbit.TimeInForcePostOnly {
+		if fills, _ := s.plan(o, s.markets[o.Market].orderbook); len(fills) > 0 {
+			o.State = string(upbit.OrderStateCancel)
+			s.release(o)
+			return o.Order, nil
+		}
+	}
+	s.execute(o)
+	return o.Order, nil
+}
+
+// testOrder checks an order like placeOrder without placing it.
+func (s *Server) testOrder(params url.Values) (any, *apiError) {
+	o, apiErr := s.newOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return o.Order, nil
+}
+
+// newOrder validates the parameters of a new order and returns it, with
+// the funds it needs checked but not yet locked.
+func (s *Server) newOrder(params url.Values) (*orderState, *apiError) {
+	volume, apiErr := decimalParam(params, "volume")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	price, apiErr := decimalParam(params, "price")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	req := upbit.PlaceOrderRequest{
+		Market:      params.Get("market"),
+		Side:        upbit.OrderSide(params.Get("side")),
+		OrdType:     upbit.OrderType(params.Get("ord_type")),
+		Volume:      volume,
+		Price:       price,
+		Identifier:  params.Get("identifier"),
+		TimeInForce: upbit.TimeInForce(params.Get("time_in_force")),
+		SMPType:     upbit.SMPType(params.Get("smp_type")),
+	}
+
+	if _, apiErr := s.knownMarket(req.Market); apiErr != nil {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidMarket, "market %s does not exist", req.Market)
+	}
+	if apiErr := s.checkOrder(&req); apiErr != nil {
+		return nil, apiErr
+	}
+	if req.Identifier != "" {
+		for _, o := range s.orders {
+			if o.identifier == req.Identifier {
+				return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "identifier %s is already in use", req.Identifier)
+			}
+		}
+	}
+
+	quote, base := marketCurrencies(req.Market)
+	o := &orderState{identifier: req.Identifier}
+	o.Order = upbit.Order{
+		UUID:            uuid.NewString(),
+		Side:            string(req.Side),
+		OrdType:         string(req.OrdType),
+		Price:           req.Price,
+		State:           string(upbit.OrderStateWait),
+		Market:          req.Market,
+		CreatedAt:       timestamp(s.now()),
+		Volume:          req.Volume,
+		RemainingVolume: req.Volume,
+		TimeInForce:     string(req.TimeInForce),
+		SMPType:         string(req.SMPType),
+	}
+
+	if req.Side == upbit.OrderSideBid {
+		total := req.Price
+		if req.OrdType == upbit.OrderTypeLimit {
+			total = req.Price.Mul(req.Volume)
+			o.perVolume = req.Price.Add(req.Price.Mul(s.fee))
+		}
+		if total.LessThan(s.minTotal) {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrUnderMinTotalBid, "최소주문금액 이상으로 주문해주세요")
+		}
+		o.currency = quote
+		o.ReservedFee = total.Mul(s.fee)
+		o.RemainingFee = o.ReservedFee
+		o.Locked = total.Add(o.ReservedFee)
+	} else {
+		if req.OrdType == upbit.OrderTypeLimit && req.Price.Mul(req.Volume).LessThan(s.minTotal) {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrUnderMinTotalAsk, "최소주문금액 이상으로 주문해주세요")
+		}
+		o.currency = base
+		o.Locked = req.Volume
+	}
+
+	if s.balanceLocked(o.currency).balance.LessThan(o.Locked) {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInsufficientFunds, "주문가능한 금액(%s)이 부족합니다.", o.currency)
+	}
+	return o, nil
+}
+
+// matchResting fills resting limit orders of a market against its book.
+func (s *Server) matchResting(market string) {
+	for _, id := range s.orderSeq {
+		if o := s.orders[id]; o.Market == market && o.State == string(upbit.OrderStateWait) {
+			s.execute(o)
+		}
+	}
+}
+
+// execute matches an order against the book and settles the result.
+// Orders that may not rest on the book are closed afterwards.
+func (s *Server) execute(o *orderState) {
+	m := s.markets[o.Market]
+	fills, complete := s.plan(o, m.orderbook)
+
+	tif := upbit.TimeInForce(o.TimeInForce)
+	if tif == upbit.TimeInForceFOK && !complete {
+		fills = nil
+	}
+	for _, f := range fills {
+		s.settle(o, m, f)
+	}
+
+	resting := o.isLimit() && (tif == "" || tif == upbit.TimeInForcePostOnly)
+	switch {
+	case resting && o.RemainingVolume.Sign() > 0:
+		return
+	case complete && len(fills) > 0:
+		o.State = string(upbit.OrderStateDone)
+	default:
+		o.State = string(upbit.OrderStateCancel)
+	}
+	s.release(o)
+}
+
+// plan returns the fills an order would get against book and whether they
+// complete it.
+func (s *Server) plan(o *orderState, book upbit.Orderbook) ([]fill, bool) {
+	bid := o.Side == string(upbit.OrderSideBid)
+	byFunds := bid && !o.isLimit()
+
+	remaining := o.RemainingVolume
+	funds := o.Locked.Sub(o.RemainingFee)
+	var fills []fill
+	for i, unit := range book.OrderbookUnits {
+		price, size := unit.BidPrice, unit.BidSize
+		if bid {
+			price, size = unit.AskPrice, unit.AskSize
+		}
+		if size.Sign() <= 0 {
+			continue
+		}
+		if o.isLimit() &&
+			(bid && price.GreaterThan(o.Price) || !bid && price.LessThan(o.Price)) {
+			break
+		}
+
+		var volume upbit.Decimal
+		if byFunds {
+			volume = funds.Div(price).Truncate(volumePrecision)
+			if size.LessThan(volume) {
+				volume = size
+			}
+			funds = funds.Sub(price.Mul(volume))
+		} else {
+			volume = remaining
+			if size.LessThan(volume) {
+				volume = size
+			}
+			remaining = remaining.Sub(volume)
+		}
+		if volume.IsZero() {
+			break
+		}
+		fills = append(fills, fill{level: i, price: price, volume: volume})
+
+		if byFunds && funds.Div(price).Truncate(volumePrecision).IsZero() || !byFunds && remaining.IsZero() {
+			return fills, true
+		}
+	}
+	return fills, false
+}
+
+// settle applies a fill to the order, the balances and the book.
+func (s *Server) settle(o *orderState, m *marketState, f fill) {
+	quote, base := marketCurrencies(o.Market)
+	funds := f.price.Mul(f.volume)
+	fee := funds.Mul(s.fee)
+
+	unit := &m.orderbook.OrderbookUnits[f.level]
+	if o.Side == string(upbit.OrderSideBid) {
+		unit.AskSize = unit.AskSize.Sub(f.volume)
+		m.orderbook.TotalAskSize = m.orderbook.TotalAskSize.Sub(f.volume)
+
+		cost := funds.Add(fee)
+		reserved := cost
+		if o.isLimit() {
+			reserved = o.perVolume.Mul(f.volume)
+		}
+		q := s.balanceLocked(quote)
+		q.locked = q.locked.Sub(reserved)
+		q.balance = q.balance.Add(reserved.Sub(cost))
+		o.Locked = o.Locked.Sub(reserved)
+		o.RemainingFee = o.RemainingFee.Sub(fee)
+
+		b := s.balanceLocked(base)
+		held := b.balance.Add(b.locked)
+		b.avgBuyPrice = b.avgBuyPrice.Mul(held).Add(funds).Div(held.Add(f.volume))
+		b.balance = b.balance.Add(f.volume)
+	} else {
+		unit.BidSize = unit.BidSize.Sub(f.volume)
+		m.orderbook.TotalBidSize = m.orderbook.TotalBidSize.Sub(f.volume)
+
+		b := s.balanceLocked(base)
+		b.locked = b.locked.Sub(f.volume)
+		o.Locked = o.Locked.Sub(f.volume)
+
+		q := s.balanceLocked(quote)
+		q.balance = q.balance.Add(funds.Sub(fee))
+	}
+
+	if o.isLimit() || o.Side == string(upbit.OrderSideAsk) {
+		o.RemainingVolume = o.RemainingVolume.Sub(f.volume)
+	}
+	o.ExecutedVolume = o.ExecutedVolume.Add(f.volume)
+	o.PaidFee = o.PaidFee.Add(fee)
+	o.TradesCount++
+	o.Trades = append(o.Trades, upbit.OrderTrade{
+		Market:    o.Market,
+		UUID:      uuid.NewString(),
+		Price:     f.price,
+		Volume:    f.volume,
+		Funds:     funds,
+		Side:      o.Side,
+		CreatedAt: timestamp(s.now()),
+	})
+}
+
+// release returns what an order still has locked to the available balance.
+func (s *Server) release(o *orderState) {
+	b := s.balanceLocked(o.currency)
+	b.locked = b.locked.Sub(o.Locked)
+	b.balance = b.balance.Add(o.Locked)
+	o.Locked = upbit.Decimal{}
+	o.RemainingFee = upbit.Decimal{}
+}
+
+func (s *Server) cancelOrder(params url.Values) (any, *apiError) {
+	o, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	switch upbit.OrderState(o.State) {
+	case upbit.OrderStateDone:
+		return nil, errorf(http.StatusBadRequest, upbit.ErrOrderExecuted, "이미 체결된 주문입니다.")
+	case upbit.OrderStateCancel:
+		return nil, errorf(http.StatusBadRequest, upbit.ErrOrderCancelled, "이미 취소된 주문입니다.")
+	}
+
+	// The response reflects the order as it was when cancellation began.
+	resp := o.Order
+	o.State = string(upbit.OrderStateCancel)
+	s.release(o)
+	return resp, nil
+}
+
+// cancelOrders cancels up to 20 orders given by "uuids[]" or
+// "identifiers[]", reporting unknown and closed orders as failed.
+func (s *Server) cancelOrders(params url.Values) (any, *apiError) {
+	key, ids := "uuid", listParam(params, "uuids")
+	if identifiers := listParam(params, "identifiers"); len(identifiers) > 0 {
+		if len(ids) > 0 {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "uuids and identifiers cannot be combined")
+		}
+		key, ids = "identifier", identifiers
+	}
+	if len(ids) == 0 || len(ids) > 20 {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "between 1 and 20 orders are required")
+	}
+
+	result := upbit.CancelOrdersResult{}
+	for _, id := range ids {
+		ref := url.Values{key: {id}}
+		o, apiErr := s.findOrder(ref)
+		if apiErr == nil {
+			_, apiErr = s.cancelOrder(ref)
+		}
+		if apiErr != nil {
+			entry := upbit.CancelledOrder{}
+			if o != nil {
+				entry = cancelledOrder(o)
+			} else if key == "uuid" {
+				entry.UUID = id
+			} else {
+				entry.Identifier = id
+			}
+			result.Failed.Orders = append(result.Failed.Orders, entry)
+			continue
+		}
+		result.Success.Orders = append(result.Success.Orders, cancelledOrder(o))
+	}
+	result.Success.Count = len(result.Success.Orders)
+	result.Failed.Count = len(result.Failed.Orders)
+	return result, nil
+}
+
+// cancelOpenOrders cancels waiting orders matching the side, market and
+// quote currency filters, oldest or newest first.
+func (s *Server) cancelOpenOrders(params url.Values) (any, *apiError) {
+	side := params.Get("cancel_side")
+	if side != "all" && side != string(upbit.OrderSideAsk) && side != string(upbit.OrderSideBid) {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "cancel_side must be all, ask or bid")
+	}
+	pairs := listParam(params, "pairs")
+	quotes := listParam(params, "quote_currencies")
+	if len(pairs) > 0 && len(quotes) > 0 {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "pairs and quote_currencies cannot be combined")
+	}
+	excluded := listParam(params, "excluded_pairs")
+	count := 20
+	if v := params.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 300 {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "count must be between 1 and 300")
+		}
+		count = n
+	}
+
+	var open []*orderState
+	for _, id := range s.orderSeq {
+		o := s.orders[id]
+		quote, _ := marketCurrencies(o.Market)
+		switch {
+		case o.State != string(upbit.OrderStateWait):
+		case side != "all" && o.Side != side:
+		case len(pairs) > 0 && !slices.Contains(pairs, o.Market):
+		case len(quotes) > 0 && !slices.Contains(quotes, quote):
+		case slices.Contains(excluded, o.Market):
+		default:
+			open = append(open, o)
+		}
+	}
+	if params.Get("order_by") != "asc" {
+		slices.Reverse(open)
+	}
+
+	result := upbit.CancelOrdersResult{}
+	for _, o := range open[:min(count, len(open))] {
+		s.cancelOrder(url.Values{"uuid": {o.UUID}})
+		result.Success.Orders = append(result.Success.Orders, cancelledOrder(o))
+	}
+	result.Success.Count = len(result.Success.Orders)
+	return result, nil
+}
+
+func cancelledOrder(o *orderState) upbit.CancelledOrder {
+	return upbit.CancelledOrder{UUID: o.UUID, Market: o.Market, Identifier: o.identifier}
+}
+
+// replaceOrder cancels an order and places its replacement on the same
+// market and side. If the replacement is rejected the cancellation is
+// undone.
+func (s *Server) replaceOrder(params url.Values) (any, *apiError) {
+	prev := url.Values{}
+	prev.Set("uuid", params.Get("prev_order_uuid"))
+	prev.Set("identifier", params.Get("prev_order_identifier"))
+	o, apiErr := s.findOrder(prev)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	next := url.Values{}
+	next.Set("market", o.Market)
+	next.Set("side", o.Side)
+	next.Set("ord_type", params.Get("new_ord_type"))
+	for _, name := range []string{"volume", "price", "identifier", "time_in_force", "smp_type"} {
+		if v := params.Get("new_" + name); v != "" {
+			next.Set(name, v)
+		}
+	}
+	if next.Get("volume") == "remain_only" {
+		next.Set("volume", o.RemainingVolume.String())
+	}
+
+	saved := o.Order
+	resp, apiErr := s.cancelOrder(prev)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	placed, apiErr := s.placeOrder(next)
+	if apiErr != nil {
+		b := s.balanceLocked(o.currency)
+		b.balance = b.balance.Sub(saved.Locked)
+		b.locked = b.locked.Add(saved.Locked)
+		o.Order = saved
+		return nil, apiErr
+	}
+	s.orders[placed.(upbit.Order).UUID].visibleAt = time.Now().Add(s.replaceDelay)
+
+	return struct {
+		upbit.Order
+		NewOrderUUID       string `json:"new_order_uuid"`
+		NewOrderIdentifier string `json:"new_order_identifier,omitempty"`
+	}{resp.(upbit.Order), placed.(upbit.Order).UUID, next.Get("identifier")}, nil
+}
+
+// === Withdrawals and deposits ===
+
+// SetWithdrawFee sets the withdrawal fee of a currency.
+func (s *Server) SetWithdrawFee(currency string, fee upbit.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.withdrawFees[currency] = fee
+}
+
+// Withdraws returns all withdrawals in creation order.
+func (s *Server) Withdraws() []upbit.Withdraw {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	withdraws := make([]upbit.Withdraw, len(s.withdraws))
+	for i, w := range s.withdraws {
+		withdraws[i] = *w
+	}
+	return withdraws
+}
+
+// CompleteWithdraw marks a pending withdrawal as done, removing its locked
+// funds. It reports whether the withdrawal was pending.
+func (s *Server) CompleteWithdraw(uuid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range s.withdraws {
+		if w.UUID != uuid || w.State != upbit.WithdrawStateProcessing {
+			continue
+		}
+		b := s.balanceLocked(w.Currency)
+		b.locked = b.locked.Sub(w.Amount.Add(w.Fee))
+		w.State = upbit.WithdrawStateDone
+		w.TxID = s.nextID("tx")
+		w.DoneAt = timestamp(s.now())
+		return true
+	}
+	return false
+}
+
+// Deposit credits amount of currency as an accepted deposit and returns its
+// UUID.
+func (s *Server) Deposit(currency string, amount upbit.Decimal) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamp(s.now())
+	d := &upbit.Deposit{
+		Type:            "deposit",
+		UUID:            uuid.NewString(),
+		Currency:        currency,
+		NetType:         currency,
+		TxID:            s.nextID("tx"),
+		State:           upbit.DepositStateAccepted,
+		CreatedAt:       now,
+		DoneAt:          now,
+		Amount:          amount,
+		TransactionType: "default",
+	}
+	s.deposits = append(s.deposits, d)
+	b := s.balanceLocked(currency)
+	b.balance = b.balance.Add(amount)
+	return d.UUID
+}
+
+// transferFields returns the filterable fields of a withdrawal or deposit.
+type transferFields[T any] func(*T) (uuid, currency, state, txid string)
+
+// listTransfers filters and pages withdrawals or deposits.
+func listTransfers[T any](records []*T, params url.Values, fields transferFields[T]) ([]T, *apiError) {
+	uuids := listParam(params, "uuids")
+	txids := listParam(params, "txids")
+
+	items := []T{}
+	for _, r := range records {
+		id, currency, state, txid := fields(r)
+		switch {
+		case params.Get("currency") != "" && currency != params.Get("currency"):
+		case params.Get("state") != "" && !strings.EqualFold(state, params.Get("state")):
+		case len(uuids) > 0 && !slices.Contains(uuids, id):
+		case len(txids) > 0 && !slices.Contains(txids, txid):
+		default:
+			items = append(items, *r)
+		}
+	}
+	if params.Get("order_by") != "asc" {
+		slices.Reverse(items)
+	}
+	return paginate(items, params, 100)
+}
+
+func withdrawFields(w *upbit.Withdraw) (string, string, string, string) {
+	return w.UUID, w.Currency, string(w.State), w.TxID
+}
+
+func depositFields(d *upbit.Deposit) (string, string, string, string) {
+	return d.UUID, d.Currency, string(d.State), d.TxID
+}
+
+func (s *Server) getWithdraws(params url.Values) (any, *apiError) {
+	return listTransfers(s.withdraws, params, withdrawFields)
+}
+
+func (s *Server) getWithdraw(params url.Values) (any, *apiError) {
+	id, apiErr := required(params, "uuid")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, w := range s.withdraws {
+		if w.UUID == id {
+			return w, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "withdraw_not_found", "출금 내역이 존재하지 않습니다.")
+}
+
+func (s *Server) getWithdrawChance(params url.Values) (any, *apiError) {
+	currency, apiErr := required(params, "currency")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	b := s.balanceLocked(currency)
+	return map[string]any{
+		"member_level": map[string]any{"security_level": 4, "fee_level": 0, "email_verified": true, "identity_auth_verified": true, "bank_account_verified": true},
+		"currency": map[string]any{
+			"code":           currency,
+			"withdraw_fee":   s.withdrawFees[currency],
+			"is_coin":        currency != "KRW",
+			"wallet_state":   upbit.WalletStateWorking,
+			"wallet_support": []string{"deposit", "withdraw"},
+		},
+		"account": s.account(currency),
+		"withdraw_limit": map[string]any{
+			"currency":        currency,
+			"minimum":         upbit.Decimal{},
+			"onetime":         b.balance,
+			"daily":           b.balance,
+			"remaining_daily": b.balance,
+			"fixed":           volumePrecision,
+			"can_withdraw":    true,
+		},
+	}, nil
+}
+
+// withdraw locks amount plus fees of currency for a new pending withdrawal.
+func (s *Server) withdraw(currency, netType, transactionType string, amount upbit.Decimal) (*upbit.Withdraw, *apiError) {
+	if amount.Sign() <= 0 {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "amount must be positive")
+	}
+	fee := s.withdrawFees[currency]
+	total := amount.Add(fee)
+
+	b := s.balanceLocked(currency)
+	if b.balance.LessThan(total) {
+		return nil, errorf(http.StatusBadRequest, upbit.ErrInsufficientFunds, "출금가능 금액이 부족합니다.")
+	}
+	b.balance = b.balance.Sub(total)
+	b.locked = b.locked.Add(total)
+
+	w := &upbit.Withdraw{
+		Type:            "withdraw",
+		UUID:            uuid.NewString(),
+		Currency:        currency,
+		NetType:         netType,
+		State:           upbit.WithdrawStateProcessing,
+		CreatedAt:       timestamp(s.now()),
+		Amount:          amount,
+		Fee:             fee,
+		TransactionType: cmp.Or(transactionType, "default"),
+	}
+	s.withdraws = append(s.withdraws, w)
+	return w, nil
+}
+
+func (s *Server) withdrawCoin(params url.Values) (any, *apiError) {
+	for _, name := range []string{"currency", "net_type", "amount", "address"} {
+		if _, apiErr := required(params, name); apiErr != nil {
+			return nil, apiErr
+		}
+	}
+	amount, apiErr := decimalParam(params, "amount")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return s.withdraw(params.Get("currency"), params.Get("net_type"), params.Get("transaction_type"), amount)
+}
+
+func (s *Server) withdrawKRW(params url.Values) (any, *apiError) {
+	if _, apiErr := required(params, "amount"); apiErr != nil {
+		return nil, apiErr
+	}
+	amount, apiErr := decimalParam(params, "amount")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return s.withdraw("KRW", "KRW", "", amount)
+}
+
+func (s *Server) getDeposits(params url.Values) (any, *apiError) {
+	return listTransfers(s.deposits, params, depositFields)
+}
+
+func (s *Server) getDeposit(params url.Values) (any, *apiError) {
+	id, apiErr := required(params, "uuid")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, d := range s.deposits {
+		if d.UUID == id {
+			return d, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "deposit_not_found", "입금 내역이 존재하지 않습니다.")
+}
+
+func (s *Server) generateDepositAddress(params url.Values) (any, *apiError) {
+	currency, apiErr := required(params, "currency")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	netType, apiErr := required(params, "net_type")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	key := currency + "/" + netType
+	addr, ok := s.addresses[key]
+	if !ok {
+		addr = upbit.DepositAddress{
+			Currency:       currency,
+			NetType:        netType,
+			DepositAddress: s.nextID(strings.ToLower(netType)),
+		}
+		s.addresses[key] = addr
+	}
+	return addr, nil
+}
+
+func (s *Server) getDepositAddresses(url.Values) (any, *apiError) {
+	addresses := []upbit.DepositAddress{}
+	for _, addr := range s.addresses {
+		addresses = append(addresses, addr)
+	}
+	slices.SortFunc(addresses, func(a, b upbit.DepositAddress) int {
+		return cmp.Or(cmp.Compare(a.Currency, b.Currency), cmp.Compare(a.NetType, b.NetType))
+	})
+	return addresses, nil
+}
+
+func (s *Server) getDepositAddress(params url.Values) (any, *apiError) {
+	currency, apiErr := required(params, "currency")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	netType, apiErr := required(params, "net_type")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	addr, ok := s.addresses[currency+"/"+netType]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "coin_address_not_found", "%s 입금주소를 찾지 못했습니다.", currency)
+	}
+	return addr, nil
+}
+
+func (s *Server) getWalletStatus(url.Values) (any, *apiError) {
+	statuses := []upbit.WalletStatus{}
+	for _, code := range s.order {
+		_, base := marketCurrencies(code)
+		if slices.ContainsFunc(statuses, func(w upbit.WalletStatus) bool { return w.Currency == base }) {
+			continue
+		}
+		statuses = append(statuses, upbit.WalletStatus{
+			Currency:    base,
+			WalletState: upbit.WalletStateWorking,
+			BlockState:  upbit.BlockStateNormal,
+			NetType:     base,
+		})
+	}
+	return statuses, nil
+}
+
+func (s *Server) getAPIKeys(url.Values) (any, *apiError) {
+	return []upbit.APIKey{{
+		AccessKey: s.accessKey,
+		ExpireAt:  timestamp(s.now().AddDate(1, 0, 0)),
+	}}, nil
+}
@@ -0,0 +1,277 @@
+package upbittest
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// maxCandles is the largest count accepted by the candle endpoints.
+const maxCandles = 200
+
+// marketState is the market data of a single market.
+type marketState struct {
+	info      upbit.Market
+	ticker    *upbit.Ticker
+	orderbook upbit.Orderbook
+	trades    []upbit.Trade             // newest first
+	candles   map[string][]upbit.Candle // keyed by unit, oldest first
+}
+
+// AddMarket registers a market.
+func (s *Server) AddMarket(m upbit.Market) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marketLocked(m.Market).info = m
+}
+
+func (s *Server) marketLocked(code string) *marketState {
+	m, ok := s.markets[code]
+	if !ok {
+		m = &marketState{
+			info:    upbit.Market{Market: code},
+			candles: make(map[string][]upbit.Candle),
+		}
+		m.orderbook.Market = code
+		s.markets[code] = m
+		s.order = append(s.order, code)
+	}
+	return m
+}
+
+// SetTicker sets the ticker of t.Market, registering the market if needed.
+func (s *Server) SetTicker(t upbit.Ticker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marketLocked(t.Market).ticker = &t
+}
+
+// SetOrderbook sets the orderbook of ob.Market. Market and crossing limit
+// orders fill against it, consuming its sizes.
+func (s *Server) SetOrderbook(ob upbit.Orderbook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ob.OrderbookUnits = slices.Clone(ob.OrderbookUnits)
+	s.marketLocked(ob.Market).orderbook = ob
+	s.matchResting(ob.Market)
+}
+
+// Orderbook returns the current orderbook of a market.
+func (s *Server) Orderbook(market string) upbit.Orderbook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ob := s.marketLocked(market).orderbook
+	ob.OrderbookUnits = slices.Clone(ob.OrderbookUnits)
+	return ob
+}
+
+// AddTrades appends trades to the trade history of their markets. Trades
+// must be added in chronological order.
+func (s *Server) AddTrades(trades ...upbit.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range trades {
+		m := s.marketLocked(t.Market)
+		m.trades = append([]upbit.Trade{t}, m.trades...)
+	}
+}
+
+// AddCandles stores candles of a market for an interval such as
+// upbit.CandleIntervalMinute1 or upbit.CandleIntervalDay. Candles must be in
+// chronological order.
+func (s *Server) AddCandles(market string, interval upbit.CandleInterval, candles ...upbit.Candle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.marketLocked(market)
+	m.candles[string(interval)] = append(m.candles[string(interval)], candles...)
+}
+
+func init() {
+	handle(http.MethodGet, "/market/all", false, (*Server).getMarkets)
+	handle(http.MethodGet, "/candles/seconds", false, candlesHandler("seconds"))
+	handle(http.MethodGet, "/candles/minutes/*", false, candlesHandler("minutes/"))
+	handle(http.MethodGet, "/candles/days", false, candlesHandler("days"))
+	handle(http.MethodGet, "/candles/weeks", false, candlesHandler("weeks"))
+	handle(http.MethodGet, "/candles/months", false, candlesHandler("months"))
+	handle(http.MethodGet, "/candles/years", false, candlesHandler("years"))
+	handle(http.MethodGet, "/ticker", false, (*Server).getTicker)
+	handle(http.MethodGet, "/ticker/all", false, (*Server).getAllTickers)
+	handle(http.MethodGet, "/orderbook", false, (*Server).getOrderbook)
+	handle(http.MethodGet, "/trades/ticks", false, (*Server).getTrades)
+}
+
+func (s *Server) getMarkets(params url.Values) (any, *apiError) {
+	markets := make([]upbit.Market, 0, len(s.order))
+	for _, code := range s.order {
+		m := s.markets[code].info
+		if params.Get("is_details") != "true" {
+			m.MarketWarning = ""
+			m.MarketEvent = nil
+		}
+		markets = append(markets, m)
+	}
+	return markets, nil
+}
+
+// knownMarket returns a registered market.
+func (s *Server) knownMarket(code string) (*marketState, *apiError) {
+	m, ok := s.markets[code]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "not_found_market", "Code not found: %s", code)
+	}
+	return m, nil
+}
+
+// parseTo parses the "to" parameter of candle requests.
+func parseTo(v string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func candlesHandler(prefix string) func(s *Server, params url.Values) (any, *apiError) {
+	return func(s *Server, params url.Values) (any, *apiError) {
+		code, apiErr := required(params, "market")
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		m, apiErr := s.knownMarket(code)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+
+		unit := prefix
+		if strings.HasSuffix(prefix, "/") {
+			unit += params.Get("_unit")
+		}
+
+		count := 1
+		if v := params.Get("count"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxCandles {
+				return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid count %q", v)
+			}
+			count = n
+		}
+
+		var to time.Time
+		if v := params.Get("to"); v != "" {
+			t, ok := parseTo(v)
+			if !ok {
+				return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid to %q", v)
+			}
+			to = t
+		}
+
+		candles := []upbit.Candle{}
+		stored := m.candles[unit]
+		for i := len(stored) - 1; i >= 0 && len(candles) < count; i-- {
+			c := stored[i]
+			if !to.IsZero() {
+				start, err := time.Parse("2006-01-02T15:04:05", c.CandleDateTimeUtc)
+				if err == nil && !start.Before(to) {
+					continue
+				}
+			}
+			candles = append(candles, c)
+		}
+		return candles, nil
+	}
+}
+
+func (s *Server) getTicker(params url.Values) (any, *apiError) {
+	codes, apiErr := required(params, "markets")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	tickers := []upbit.Ticker{}
+	for _, code := range strings.Split(codes, ",") {
+		m, apiErr := s.knownMarket(code)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if m.ticker != nil {
+			tickers = append(tickers, *m.ticker)
+		}
+	}
+	return tickers, nil
+}
+
+func (s *Server) getAllTickers(params url.Values) (any, *apiError) {
+	quotes := listParam(params, "quote_currencies")
+	tickers := []upbit.Ticker{}
+	for _, code := range s.order {
+		m := s.markets[code]
+		quote, _, _ := strings.Cut(code, "-")
+		if m.ticker == nil || (len(quotes) > 0 && !slices.Contains(quotes, quote)) {
+			continue
+		}
+		tickers = append(tickers, *m.ticker)
+	}
+	return tickers, nil
+}
+
+func (s *Server) getOrderbook(params url.Values) (any, *apiError) {
+	codes, apiErr := required(params, "markets")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	books := []upbit.Orderbook{}
+	for _, code := range strings.Split(codes, ",") {
+		m, apiErr := s.knownMarket(code)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		books = append(books, m.orderbook)
+	}
+	return books, nil
+}
+
+func (s *Server) getTrades(params url.Values) (any, *apiError) {
+	code, apiErr := required(params, "market")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	m, apiErr := s.knownMarket(code)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	count := 1
+	if v := params.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid count %q", v)
+		}
+		count = n
+	}
+
+	var cursor int64
+	if v := params.Get("cursor"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid cursor %q", v)
+		}
+		cursor = n
+	}
+
+	trades := []upbit.Trade{}
+	for _, t := range m.trades {
+		if cursor > 0 && t.SequentialID >= cursor {
+			continue
+		}
+		trades = append(trades, t)
+		if len(trades) == count {
+			break
+		}
+	}
+	return trades, nil
+}
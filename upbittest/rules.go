@@ -0,0 +1,150 @@
+package upbittest
+
+import (
+	"net/http"
+	"strings"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// defaultTickTables are the tick sizes the fake exchange enforces, by quote
+// currency. They are kept apart from the SDK's own tables so that tests
+// against the fake catch mistakes in client-side validation.
+var defaultTickTables = map[string][][2]string{
+	"KRW": {
+		{"2000000", "1000"},
+		{"1000000", "500"},
+		{"500000", "100"},
+		{"100000", "50"},
+		{"10000", "10"},
+		{"1000", "1"},
+		{"100", "0.1"},
+		{"10", "0.01"},
+		{"1", "0.001"},
+		{"0.1", "0.0001"},
+		{"0.01", "0.00001"},
+		{"0.001", "0.000001"},
+		{"0.0001", "0.0000001"},
+		{"0", "0.00000001"},
+	},
+	"BTC": {
+		{"0", "0.00000001"},
+	},
+	"USDT": {
+		{"10", "0.01"},
+		{"1", "0.001"},
+		{"0.1", "0.0001"},
+		{"0.01", "0.00001"},
+		{"0.001", "0.000001"},
+		{"0.0001", "0.0000001"},
+		{"0", "0.00000001"},
+	},
+}
+
+func newTickTables() map[string]upbit.TickTable {
+	tables := make(map[string]upbit.TickTable, len(defaultTickTables))
+	for quote, bands := range defaultTickTables {
+		table := make(upbit.TickTable, len(bands))
+		for i, b := range bands {
+			table[i] = upbit.TickRule{MinPrice: upbit.MustParseDecimal(b[0]), TickSize: upbit.MustParseDecimal(b[1])}
+		}
+		tables[quote] = table
+	}
+	return tables
+}
+
+// SetTickTable sets the tick sizes enforced for markets quoted in quote,
+// ordered by descending MinPrice like upbit.TickTable.
+func (s *Server) SetTickTable(quote string, table upbit.TickTable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickTables[strings.ToUpper(quote)] = append(upbit.TickTable(nil), table...)
+}
+
+// tickSize returns the tick size of market at price.
+func (s *Server) tickSize(market string, price upbit.Decimal) (upbit.Decimal, bool) {
+	quote, _ := marketCurrencies(market)
+	table, ok := s.tickTables[quote]
+	if !ok || len(table) == 0 {
+		return upbit.Decimal{}, false
+	}
+	for _, rule := range table {
+		if price.Cmp(rule.MinPrice) >= 0 {
+			return rule.TickSize, true
+		}
+	}
+	return table[len(table)-1].TickSize, true
+}
+
+// checkOrder applies the exchange's rules for order parameters that do not
+// depend on balances.
+func (s *Server) checkOrder(req *upbit.PlaceOrderRequest) *apiError {
+	bad := func(name upbit.ErrorCode, format string, args ...any) *apiError {
+		return errorf(http.StatusBadRequest, name, format, args...)
+	}
+
+	if req.Market == "" {
+		return bad(upbit.ErrInvalidParameter, "market is required")
+	}
+	if req.Side != upbit.OrderSideBid && req.Side != upbit.OrderSideAsk {
+		return bad(upbit.ErrInvalidParameter, "invalid side %q", req.Side)
+	}
+	if req.Volume.Sign() < 0 {
+		return bad(upbit.ErrInvalidVolume, "invalid volume")
+	}
+	if req.Price.Sign() < 0 {
+		return bad(upbit.ErrInvalidPrice, "invalid price")
+	}
+
+	switch req.OrdType {
+	case upbit.OrderTypeLimit:
+		if req.Price.IsZero() || req.Volume.IsZero() {
+			return bad(upbit.ErrInvalidParameter, "limit orders require price and volume")
+		}
+		tick, ok := s.tickSize(req.Market, req.Price)
+		if !ok {
+			return bad(upbit.ErrInvalidMarket, "market %s does not exist", req.Market)
+		}
+		if !req.Price.DivRound(tick, 0).Mul(tick).Equal(req.Price) {
+			return bad(upbit.ErrInvalidPrice, "price %s is not a multiple of tick size %s", req.Price, tick)
+		}
+	case upbit.OrderTypePrice:
+		if req.Side != upbit.OrderSideBid || req.Price.IsZero() || !req.Volume.IsZero() {
+			return bad(upbit.ErrInvalidParameter, "price orders are bids with price and without volume")
+		}
+	case upbit.OrderTypeMarket:
+		if req.Side != upbit.OrderSideAsk || req.Volume.IsZero() || !req.Price.IsZero() {
+			return bad(upbit.ErrInvalidParameter, "market orders are asks with volume and without price")
+		}
+	case upbit.OrderTypeBest:
+		if req.TimeInForce != upbit.TimeInForceIOC && req.TimeInForce != upbit.TimeInForceFOK {
+			return bad(upbit.ErrInvalidParameter, "best orders require ioc or fok")
+		}
+		if req.Side == upbit.OrderSideBid && req.Price.IsZero() || req.Side == upbit.OrderSideAsk && req.Volume.IsZero() {
+			return bad(upbit.ErrInvalidParameter, "best bids require price and best asks require volume")
+		}
+	default:
+		return bad(upbit.ErrInvalidParameter, "invalid ord_type %q", req.OrdType)
+	}
+
+	switch req.TimeInForce {
+	case "":
+	case upbit.TimeInForceIOC, upbit.TimeInForceFOK:
+		if req.OrdType != upbit.OrderTypeLimit && req.OrdType != upbit.OrderTypeBest {
+			return bad(upbit.ErrInvalidParameter, "%s requires a limit or best order", req.TimeInForce)
+		}
+	case upbit.TimeInForcePostOnly:
+		if req.OrdType != upbit.OrderTypeLimit || req.SMPType != "" {
+			return bad(upbit.ErrInvalidParameter, "post_only requires a limit order without smp_type")
+		}
+	default:
+		return bad(upbit.ErrInvalidParameter, "invalid time_in_force %q", req.TimeInForce)
+	}
+
+	switch req.SMPType {
+	case "", upbit.SMPTypeCancelMaker, upbit.SMPTypeCancelTaker, upbit.SMPTypeReduce:
+	default:
+		return bad(upbit.ErrInvalidParameter, "invalid smp_type %q", req.SMPType)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+// Package upbittest provides an in-memory fake of the Upbit REST API for
+// tests.
+//
+// A Server keeps markets, balances, orders, deposits and withdrawals in
+// memory. Placing an order locks funds and matches it against the configured
+// orderbook; cancelling releases what remains locked. Authenticated endpoints
+// verify the JWT signature, nonce and query_hash like the real exchange.
+//
+//	srv := upbittest.NewServer("access", "secret")
+//	defer srv.Close()
+//	srv.SetBalance("KRW", upbit.MustParseDecimal("1000000"))
+//
+//	client := upbit.NewClient("access", "secret", upbit.WithBaseURL(srv.URL()))
+package upbittest
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// kst is the time zone of timestamps reported by the exchange API.
+var kst = time.FixedZone("KST", 9*60*60)
+
+// Server is an in-memory fake Upbit exchange. It is safe for concurrent use.
+type Server struct {
+	server    *httptest.Server
+	accessKey string
+	secretKey string
+
+	mu           sync.Mutex
+	now          func() time.Time
+	fee          upbit.Decimal
+	minTotal     upbit.Decimal
+	tickTables   map[string]upbit.TickTable
+	nonces       map[string]bool
+	markets      map[string]*marketState
+	order        []string // market codes in insertion order
+	balances     map[string]*balance
+	orders       map[string]*orderState
+	orderSeq     []string // order UUIDs in creation order
+	withdraws    []*upbit.Withdraw
+	withdrawFees map[string]upbit.Decimal
+	deposits     []*upbit.Deposit
+	addresses    map[string]upbit.DepositAddress
+	seq          int64
+	replaceDelay time.Duration
+}
+
+// balance is the holdings of a single currency.
+type balance struct {
+	balance     upbit.Decimal
+	locked      upbit.Decimal
+	avgBuyPrice upbit.Decimal
+}
+
+// NewServer starts a fake exchange accepting tokens signed with the given
+// key pair. Callers must Close it when done.
+func NewServer(accessKey, secretKey string) *Server {
+	s := &Server{
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		now:          time.Now,
+		fee:          upbit.MustParseDecimal("0.0005"),
+		minTotal:     upbit.DecimalFromInt(5000),
+		tickTables:   newTickTables(),
+		nonces:       make(map[string]bool),
+		markets:      make(map[string]*marketState),
+		balances:     make(map[string]*balance),
+		orders:       make(map[string]*orderState),
+		addresses:    make(map[string]upbit.DepositAddress),
+		withdrawFees: make(map[string]upbit.Decimal),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL to pass to upbit.WithBaseURL.
+func (s *Server) URL() string {
+	return s.server.URL + "/v1"
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetClock replaces the server's clock, which stamps orders, deposits and
+// withdrawals.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetReplaceDelay hides orders placed by cancel_and_new from lookups for d,
+// as the real exchange creates the replacement asynchronously.
+func (s *Server) SetReplaceDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replaceDelay = d
+}
+
+// SetFee sets the trading fee rate applied to both sides.
+func (s *Server) SetFee(fee upbit.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fee = fee
+}
+
+// SetMinTotal sets the minimum order total of every market.
+func (s *Server) SetMinTotal(total upbit.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minTotal = total
+}
+
+// SetBalance sets the available balance of a currency.
+func (s *Server) SetBalance(currency string, amount upbit.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balanceLocked(currency).balance = amount
+}
+
+// Balance returns the available and locked amounts of a currency.
+func (s *Server) Balance(currency string) (available, locked upbit.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.balanceLocked(currency)
+	return b.balance, b.locked
+}
+
+func (s *Server) balanceLocked(currency string) *balance {
+	b, ok := s.balances[currency]
+	if !ok {
+		b = &balance{}
+		s.balances[currency] = b
+	}
+	return b
+}
+
+// nextID returns a unique identifier for server-generated records.
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%08d", prefix, s.seq)
+}
+
+// timestamp formats t like the exchange API does.
+func timestamp(t time.Time) string {
+	return t.In(kst).Format("2006-01-02T15:04:05-07:00")
+}
+
+// apiError is an error response.
+type apiError struct {
+	status int
+	name   upbit.ErrorCode
+	msg    string
+}
+
+func errorf(status int, name upbit.ErrorCode, format string, args ...any) *apiError {
+	return &apiError{status: status, name: name, msg: fmt.Sprintf(format, args...)}
+}
+
+// route is a registered endpoint.
+type route struct {
+	method string
+	path   string
+	auth   bool
+	handle func(s *Server, params url.Values) (any, *apiError)
+}
+
+var routes []route
+
+func handle(method, path string, auth bool, h func(s *Server, params url.Values) (any, *apiError)) {
+	routes = append(routes, route{method: method, path: path, auth: auth, handle: h})
+}
+
+// match returns the route for a request. Paths ending in "/*" match any
+// single trailing segment, which is passed as the "_unit" parameter.
+func match(method, path string) (route, string, bool) {
+	for _, r := range routes {
+		if r.method != method {
+			continue
+		}
+		if r.path == path {
+			return r, "", true
+		}
+		if prefix, ok := strings.CutSuffix(r.path, "*"); ok && strings.HasPrefix(path, prefix) {
+			if rest := path[len(prefix):]; rest != "" && !strings.Contains(rest, "/") {
+				return r, rest, true
+			}
+		}
+	}
+	return route{}, "", false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1")
+	if !ok {
+		writeError(w, errorf(http.StatusNotFound, "not_found", "unknown path %s", r.URL.Path))
+		return
+	}
+	rt, unit, ok := match(r.Method, path)
+	if !ok {
+		writeError(w, errorf(http.StatusNotFound, "not_found", "unknown endpoint %s %s", r.Method, path))
+		return
+	}
+
+	params, canonical, err := readParams(r)
+	if err != nil {
+		writeError(w, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "%v", err))
+		return
+	}
+
+	if rt.auth {
+		if apiErr := s.authenticate(r.Header.Get("Authorization"), canonical); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+	if unit != "" {
+		params.Set("_unit", unit)
+	}
+
+	s.mu.Lock()
+	result, apiErr := rt.handle(s, params)
+	s.mu.Unlock()
+
+	w.Header().Set("Remaining-Req", remainingReq(r.Method, path))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// readParams returns the request parameters and the canonical string the
+// query_hash is computed over. GET and DELETE parameters are read from the
+// query string and POST parameters from a JSON body.
+func readParams(r *http.Request) (url.Values, string, error) {
+	if r.Method != http.MethodPost {
+		params, err := url.ParseQuery(r.URL.RawQuery)
+		if err != nil {
+			return nil, "", err
+		}
+		canonical, err := url.QueryUnescape(r.URL.RawQuery)
+		if err != nil {
+			return nil, "", err
+		}
+		return params, canonical, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return url.Values{}, "", nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil, "", fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type"))
+	}
+	return decodeJSONParams(body)
+}
+
+// decodeJSONParams flattens a JSON object of scalars and scalar arrays into
+// parameters. The canonical string lists them in body order, with array
+// elements as repeated "key[]=value" pairs.
+func decodeJSONParams(body []byte) (url.Values, string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, "", fmt.Errorf("request body must be a JSON object")
+	}
+
+	params := url.Values{}
+	var pairs []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, "", err
+		}
+		key := tok.(string)
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, "", err
+		}
+		values, isArray := v.([]any)
+		switch {
+		case isArray:
+			key += "[]"
+		case v == nil:
+			continue
+		default:
+			values = []any{v}
+		}
+		for _, v := range values {
+			s := fmt.Sprint(v)
+			params.Add(key, s)
+			pairs = append(pairs, key+"="+s)
+		}
+	}
+	return params, strings.Join(pairs, "&"), nil
+}
+
+// authenticate verifies a bearer token the way Upbit does.
+func (s *Server) authenticate(header, canonical string) *apiError {
+	raw, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return errorf(http.StatusUnauthorized, upbit.ErrJWTVerificationFailed, "missing bearer token")
+	}
+
+	token, err := jwt.Parse(raw, func(*jwt.Token) (any, error) {
+		return []byte(s.secretKey), nil
+	}, jwt.WithValidMethods([]string{"HS256", "HS512"}))
+	if err != nil || !token.Valid {
+		return errorf(http.StatusUnauthorized, upbit.ErrJWTVerificationFailed, "invalid token: %v", err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+
+	if claims["access_key"] != s.accessKey {
+		return errorf(http.StatusUnauthorized, upbit.ErrJWTVerificationFailed, "unknown access key")
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	if nonce == "" {
+		return errorf(http.StatusUnauthorized, upbit.ErrJWTVerificationFailed, "missing nonce")
+	}
+	s.mu.Lock()
+	used := s.nonces[nonce]
+	s.nonces[nonce] = true
+	s.mu.Unlock()
+	if used {
+		return errorf(http.StatusUnauthorized, upbit.ErrNonceUsed, "nonce already used")
+	}
+
+	hash, _ := claims["query_hash"].(string)
+	if canonical == "" {
+		if hash != "" {
+			return errorf(http.StatusUnauthorized, upbit.ErrInvalidQuery, "unexpected query_hash")
+		}
+		return nil
+	}
+	sum := sha512.Sum512([]byte(canonical))
+	if hash != hex.EncodeToString(sum[:]) {
+		return errorf(http.StatusUnauthorized, upbit.ErrInvalidQuery, "query_hash does not match %q", canonical)
+	}
+	return nil
+}
+
+// remainingReq returns a Remaining-Req header value for an endpoint.
+func remainingReq(method, path string) string {
+	switch {
+	case path == "/market/all":
+		return "group=market; min=600; sec=9"
+	case strings.HasPrefix(path, "/candles/"):
+		return "group=candles; min=600; sec=9"
+	case strings.HasPrefix(path, "/ticker"):
+		return "group=ticker; min=600; sec=9"
+	case path == "/orderbook":
+		return "group=orderbook; min=600; sec=9"
+	case strings.HasPrefix(path, "/trades/"):
+		return "group=trades; min=600; sec=9"
+	case method == http.MethodPost && (path == "/orders" || path == "/orders/test" || path == "/orders/cancel_and_new"):
+		return "group=order; min=480; sec=7"
+	case method == http.MethodDelete && path == "/orders/open":
+		return "group=order-cancel-all; min=29; sec=0"
+	default:
+		return "group=default; min=1800; sec=29"
+	}
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(upbit.APIError{Err: upbit.ErrorDetail{Name: string(e.name), Message: e.msg}})
+}
+
+// required returns a required parameter.
+func required(params url.Values, name string) (string, *apiError) {
+	v := params.Get(name)
+	if v == "" {
+		return "", errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "%s is required", name)
+	}
+	return v, nil
+}
+
+// decimalParam parses an optional decimal parameter.
+func decimalParam(params url.Values, name string) (upbit.Decimal, *apiError) {
+	v := params.Get(name)
+	if v == "" {
+		return upbit.Decimal{}, nil
+	}
+	d, err := upbit.ParseDecimal(v)
+	if err != nil {
+		return upbit.Decimal{}, errorf(http.StatusBadRequest, upbit.ErrInvalidParameter, "invalid %s %q", name, v)
+	}
+	return d, nil
+}
+
+// listParam returns the values of an array parameter sent either as
+// repeated "name[]" keys or as a comma-separated "name" value.
+func listParam(params url.Values, name string) []string {
+	if values := params[name+"[]"]; len(values) > 0 {
+		return values
+	}
+	if v := params.Get(name); v != "" {
+		return strings.Split(v, ",")
+	}
+	return nil
+}
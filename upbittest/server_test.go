@@ -0,0 +1,642 @@
+package upbittest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+func newTestServer(t *testing.T) (*Server, *upbit.Client) {
+	t.Helper()
+	srv := NewServer("access", "secret")
+	t.Cleanup(srv.Close)
+
+	srv.AddMarket(upbit.Market{Market: "KRW-BTC", KoreanName: "비트코인", EnglishName: "Bitcoin"})
+	srv.SetOrderbook(upbit.Orderbook{
+		Market: "KRW-BTC",
+		OrderbookUnits: []upbit.OrderbookUnit{
+			{AskPrice: dec("50010000"), AskSize: dec("0.1"), BidPrice: dec("50000000"), BidSize: dec("0.1")},
+			{AskPrice: dec("50020000"), AskSize: dec("1"), BidPrice: dec("49990000"), BidSize: dec("1")},
+		},
+	})
+
+	client := upbit.NewClient("access", "secret")
+	client.SetBaseURL(srv.URL())
+	return srv, client
+}
+
+func dec(s string) upbit.Decimal {
+	return upbit.MustParseDecimal(s)
+}
+
+func expectAPIError(t *testing.T, err error, name upbit.ErrorCode) {
+	t.Helper()
+	var apiErr *upbit.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError %s, got %v", name, err)
+	}
+	if apiErr.Err.Code() != name {
+		t.Errorf("Expected error %s, got %s", name, apiErr.Err.Name)
+	}
+}
+
+func TestQuotation(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetTicker(upbit.Ticker{Market: "KRW-BTC", TradePrice: dec("50005000")})
+	srv.AddCandles("KRW-BTC", upbit.CandleIntervalMinute1,
+		upbit.Candle{Market: "KRW-BTC", CandleDateTimeUtc: "2024-01-01T00:00:00"},
+		upbit.Candle{Market: "KRW-BTC", CandleDateTimeUtc: "2024-01-01T00:01:00"},
+		upbit.Candle{Market: "KRW-BTC", CandleDateTimeUtc: "2024-01-01T00:02:00"},
+	)
+	srv.AddTrades(
+		upbit.Trade{Market: "KRW-BTC", SequentialID: 1},
+		upbit.Trade{Market: "KRW-BTC", SequentialID: 2},
+		upbit.Trade{Market: "KRW-BTC", SequentialID: 3},
+	)
+
+	markets, err := client.GetMarkets(false)
+	if err != nil {
+		t.Fatalf("GetMarkets failed: %v", err)
+	}
+	if len(markets) != 1 || markets[0].Market != "KRW-BTC" {
+		t.Errorf("Unexpected markets %+v", markets)
+	}
+
+	tickers, err := client.GetTicker([]string{"KRW-BTC"})
+	if err != nil {
+		t.Fatalf("GetTicker failed: %v", err)
+	}
+	if len(tickers) != 1 || tickers[0].TradePrice.String() != "50005000" {
+		t.Errorf("Unexpected tickers %+v", tickers)
+	}
+
+	candles, err := client.GetMinuteCandles("KRW-BTC", upbit.CandleUnit1, "2024-01-01T00:02:00Z", 5)
+	if err != nil {
+		t.Fatalf("GetMinuteCandles failed: %v", err)
+	}
+	if len(candles) != 2 || candles[1].CandleDateTimeUtc != "2024-01-01T00:01:00" {
+		t.Errorf("Expected the two candles before to, got %+v", candles)
+	}
+
+	trades, err := client.GetTrades("KRW-BTC", "", 5, "3", 0)
+	if err != nil {
+		t.Fatalf("GetTrades failed: %v", err)
+	}
+	if len(trades) != 2 || trades[0].SequentialID != 2 {
+		t.Errorf("Expected trades before cursor 3, got %+v", trades)
+	}
+
+	_, err = client.GetTicker([]string{"KRW-XYZ"})
+	expectAPIError(t, err, "not_found_market")
+}
+
+func TestLimitOrderLifecycle(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+
+	order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypeLimit,
+		Volume:  dec("0.1"),
+		Price:   dec("49000000"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if order.State != "wait" || order.Locked.String() != "4902450" {
+		t.Errorf("Expected waiting order locking 4902450, got %s %s", order.State, order.Locked)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "5097550" || locked.String() != "4902450" {
+		t.Errorf("Unexpected KRW balance %s/%s", available, locked)
+	}
+
+	// Half of the order fills when the book moves through its price.
+	srv.SetOrderbook(upbit.Orderbook{
+		Market: "KRW-BTC",
+		OrderbookUnits: []upbit.OrderbookUnit{
+			{AskPrice: dec("49000000"), AskSize: dec("0.05"), BidPrice: dec("48990000"), BidSize: dec("1")},
+		},
+	})
+
+	detail, err := client.GetOrder(order.UUID)
+	if err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if detail.ExecutedVolume.String() != "0.05" || len(detail.Trades) != 1 || detail.State != "wait" {
+		t.Errorf("Expected a partial fill of 0.05, got %+v", detail)
+	}
+	if available, _ := srv.Balance("BTC"); available.String() != "0.05" {
+		t.Errorf("Expected 0.05 BTC, got %s", available)
+	}
+
+	if _, err := client.CancelOrder(order.UUID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "7548775" || !locked.IsZero() {
+		t.Errorf("Expected the rest to be released, got %s/%s", available, locked)
+	}
+
+	_, err = client.CancelOrder(order.UUID)
+	expectAPIError(t, err, upbit.ErrOrderCancelled)
+}
+
+func TestReplaceOrder(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+
+	order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:     "KRW-BTC",
+		Side:       upbit.OrderSideBid,
+		OrdType:    upbit.OrderTypeLimit,
+		Volume:     dec("0.1"),
+		Price:      dec("49000000"),
+		Identifier: "first",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	result, err := client.ReplaceOrder(&upbit.ReplaceOrderRequest{
+		PrevOrderIdentifier: "first",
+		NewOrdType:          upbit.OrderTypeLimit,
+		NewPrice:            dec("48000000"),
+		RemainingVolume:     true,
+		NewIdentifier:       "second",
+	})
+	if err != nil {
+		t.Fatalf("ReplaceOrder failed: %v", err)
+	}
+	if result.Cancelled.UUID != order.UUID || result.NewOrderIdentifier != "second" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if result.New.Price.String() != "48000000" || result.New.Volume.String() != "0.1" || result.New.Side != "bid" {
+		t.Errorf("Unexpected replacement %+v", result.New)
+	}
+	if prev, _ := srv.Order(order.UUID); prev.State != "cancel" {
+		t.Errorf("Expected the previous order to be cancelled, got %s", prev.State)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "5197600" || locked.String() != "4802400" {
+		t.Errorf("Unexpected KRW balance %s/%s", available, locked)
+	}
+
+	// A rejected replacement leaves the previous order resting.
+	_, err = client.ReplaceOrder(&upbit.ReplaceOrderRequest{
+		PrevOrderUUID: result.NewOrderUUID,
+		NewOrdType:    upbit.OrderTypeLimit,
+		NewPrice:      dec("48000001"),
+		NewVolume:     dec("0.1"),
+	})
+	expectAPIError(t, err, upbit.ErrInvalidPrice)
+	if prev, _ := srv.Order(result.NewOrderUUID); prev.State != "wait" {
+		t.Errorf("Expected the order to keep resting, got %s", prev.State)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "5197600" || locked.String() != "4802400" {
+		t.Errorf("Expected the balance to be restored, got %s/%s", available, locked)
+	}
+}
+
+func TestReplaceOrderSMP(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+
+	order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypeLimit,
+		Volume:  dec("0.1"),
+		Price:   dec("49000000"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	result, err := client.ReplaceOrder(&upbit.ReplaceOrderRequest{
+		PrevOrderUUID:   order.UUID,
+		NewOrdType:      upbit.OrderTypeLimit,
+		NewPrice:        dec("48000000"),
+		RemainingVolume: true,
+		NewSMPType:      upbit.SMPTypeCancelMaker,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceOrder failed: %v", err)
+	}
+	if result.New.SMPType != "cancel_maker" {
+		t.Errorf("Expected the replacement to keep smp_type cancel_maker, got %q", result.New.SMPType)
+	}
+	if placed, _ := srv.Order(result.NewOrderUUID); placed.SMPType != "cancel_maker" {
+		t.Errorf("Expected the server to record smp_type cancel_maker, got %q", placed.SMPType)
+	}
+}
+
+func TestReplaceOrderDelayed(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+	srv.SetReplaceDelay(time.Hour)
+
+	order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypeLimit,
+		Volume:  dec("0.1"),
+		Price:   dec("49000000"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// The replacement is not visible yet, which must not read as a failure
+	// a caller would retry.
+	result, err := client.ReplaceOrder(&upbit.ReplaceOrderRequest{
+		PrevOrderUUID:   order.UUID,
+		NewOrdType:      upbit.OrderTypeLimit,
+		NewPrice:        dec("48000000"),
+		RemainingVolume: true,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceOrder failed: %v", err)
+	}
+	if result.New.UUID != result.NewOrderUUID || result.New.State != "wait" || result.New.Market != "KRW-BTC" {
+		t.Errorf("Unexpected replacement %+v", result.New)
+	}
+	if _, err := client.GetOrder(result.NewOrderUUID); !errors.Is(err, upbit.ErrOrderNotFound) {
+		t.Errorf("Expected the replacement to be hidden, got %v", err)
+	}
+	if placed, ok := srv.Order(result.NewOrderUUID); !ok || placed.Price.String() != "48000000" {
+		t.Errorf("Expected the replacement to be placed, got %+v", placed)
+	}
+}
+
+func TestBulkCancel(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+	srv.SetBalance("BTC", dec("1"))
+
+	place := func(side upbit.OrderSide, price string) string {
+		t.Helper()
+		order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+			Market:  "KRW-BTC",
+			Side:    side,
+			OrdType: upbit.OrderTypeLimit,
+			Volume:  dec("0.01"),
+			Price:   dec(price),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		return order.UUID
+	}
+	first := place(upbit.OrderSideBid, "49000000")
+	place(upbit.OrderSideBid, "48000000")
+	place(upbit.OrderSideBid, "47000000")
+	place(upbit.OrderSideAsk, "51000000")
+
+	result, err := client.CancelOrders([]string{first, "missing"})
+	if err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+	if result.Success.Count != 1 || result.Success.Orders[0].UUID != first {
+		t.Errorf("Expected %s to be cancelled, got %+v", first, result.Success)
+	}
+	if result.Failed.Count != 1 || result.Failed.Orders[0].UUID != "missing" {
+		t.Errorf("Expected the unknown order to fail, got %+v", result.Failed)
+	}
+
+	result, err = client.CancelOpenOrders(&upbit.CancelOpenOrdersRequest{
+		Side:    upbit.OrderSideBid,
+		Markets: []string{"KRW-BTC"},
+		Count:   1,
+		OrderBy: "asc",
+	})
+	if err != nil {
+		t.Fatalf("CancelOpenOrders failed: %v", err)
+	}
+	if result.Success.Count != 1 {
+		t.Fatalf("Expected one cancelled order, got %+v", result)
+	}
+	if o, _ := srv.Order(result.Success.Orders[0].UUID); o.Price.String() != "48000000" || o.State != "cancel" {
+		t.Errorf("Expected the oldest open bid to be cancelled, got %+v", o)
+	}
+
+	open, err := client.GetOrders(&upbit.GetOrdersRequest{Market: "KRW-BTC"})
+	if err != nil {
+		t.Fatalf("GetOrders failed: %v", err)
+	}
+	if len(open) != 2 {
+		t.Errorf("Expected a bid and an ask to remain open, got %d orders", len(open))
+	}
+}
+
+func TestOrderTestEndpoint(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+
+	order, err := client.TestOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypeLimit,
+		Volume:  dec("0.1"),
+		Price:   dec("49000000"),
+	})
+	if err != nil {
+		t.Fatalf("TestOrder failed: %v", err)
+	}
+	if order.UUID == "" || order.Locked.String() != "4902450" {
+		t.Errorf("Unexpected test order %+v", order)
+	}
+	if len(srv.Orders()) != 0 {
+		t.Errorf("Expected no order to be placed, got %d", len(srv.Orders()))
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "10000000" || !locked.IsZero() {
+		t.Errorf("Expected the balance to be untouched, got %s/%s", available, locked)
+	}
+
+	_, err = client.TestOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypeLimit,
+		Volume:  dec("1"),
+		Price:   dec("49000000"),
+	})
+	expectAPIError(t, err, upbit.ErrInsufficientFunds)
+}
+
+func TestPostOnlyOrders(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000000"))
+
+	post := func(price string) *upbit.Order {
+		t.Helper()
+		order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+			Market:      "KRW-BTC",
+			Side:        upbit.OrderSideBid,
+			OrdType:     upbit.OrderTypeLimit,
+			Volume:      dec("0.01"),
+			Price:       dec(price),
+			TimeInForce: upbit.TimeInForcePostOnly,
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		return order
+	}
+
+	// Crossing the best ask would take liquidity.
+	if order := post("50010000"); order.State != "cancel" || !order.ExecutedVolume.IsZero() {
+		t.Errorf("Expected a crossing post-only order to be cancelled unfilled, got %+v", order)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "10000000" || !locked.IsZero() {
+		t.Errorf("Expected the funds to be released, got %s/%s", available, locked)
+	}
+
+	// A resting post-only order fills as a maker when the book moves.
+	order := post("50000000")
+	if order.State != "wait" {
+		t.Fatalf("Expected the post-only order to rest, got %s", order.State)
+	}
+	srv.SetOrderbook(upbit.Orderbook{
+		Market: "KRW-BTC",
+		OrderbookUnits: []upbit.OrderbookUnit{
+			{AskPrice: dec("50000000"), AskSize: dec("1"), BidPrice: dec("49990000"), BidSize: dec("1")},
+		},
+	})
+	if o, _ := srv.Order(order.UUID); o.State != "done" {
+		t.Errorf("Expected the resting order to fill, got %s", o.State)
+	}
+
+	withSMP := &upbit.PlaceOrderRequest{
+		Market:      "KRW-BTC",
+		Side:        upbit.OrderSideBid,
+		OrdType:     upbit.OrderTypeLimit,
+		Volume:      dec("0.01"),
+		Price:       dec("49000000"),
+		TimeInForce: upbit.TimeInForcePostOnly,
+		SMPType:     upbit.SMPTypeCancelTaker,
+	}
+	// PlaceOrder rejects the combination before sending it; TestOrder
+	// leaves it to the server.
+	if _, err := client.PlaceOrder(withSMP); !errors.Is(err, upbit.ErrInvalidParameter) {
+		t.Errorf("Expected invalid_parameter, got %v", err)
+	}
+	_, err := client.TestOrder(withSMP)
+	expectAPIError(t, err, upbit.ErrInvalidParameter)
+}
+
+func TestMarketOrders(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetFee(upbit.Decimal{})
+	srv.SetBalance("KRW", dec("10000000"))
+
+	// 6,001,400 KRW buys the 0.1 BTC at the best ask and 0.02 BTC above it.
+	bid, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypePrice,
+		Price:   dec("6001400"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if bid.State != "done" || bid.ExecutedVolume.String() != "0.12" || bid.TradesCount != 2 {
+		t.Errorf("Unexpected market bid %+v", bid)
+	}
+	if available, locked := srv.Balance("KRW"); available.String() != "3998600" || !locked.IsZero() {
+		t.Errorf("Unexpected KRW balance %s/%s", available, locked)
+	}
+
+	ask, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideAsk,
+		OrdType: upbit.OrderTypeMarket,
+		Volume:  dec("0.12"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if ask.State != "done" || ask.ExecutedVolume.String() != "0.12" {
+		t.Errorf("Unexpected market ask %+v", ask)
+	}
+	if available, _ := srv.Balance("KRW"); available.String() != "9998400" {
+		t.Errorf("Expected 9998400 KRW after selling, got %s", available)
+	}
+	if book := srv.Orderbook("KRW-BTC"); book.OrderbookUnits[0].AskSize.Sign() != 0 || book.OrderbookUnits[1].BidSize.String() != "0.98" {
+		t.Errorf("Expected fills to consume the book, got %+v", book.OrderbookUnits)
+	}
+}
+
+func TestOrderRejections(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("10000"))
+
+	_, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market: "KRW-BTC", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypeLimit,
+		Volume: dec("1"), Price: dec("50000000"),
+	})
+	expectAPIError(t, err, upbit.ErrInsufficientFunds)
+
+	_, err = client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market: "KRW-BTC", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypePrice,
+		Price: dec("1000"),
+	})
+	expectAPIError(t, err, upbit.ErrUnderMinTotalBid)
+
+	_, err = client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market: "KRW-BTC", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypeLimit,
+		Volume: dec("1"), Price: dec("5000.5"),
+	})
+	expectAPIError(t, err, upbit.ErrInvalidPrice)
+
+	// FOK orders that cannot fill completely are cancelled without fills.
+	srv.SetBalance("KRW", dec("100000000"))
+	order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market: "KRW-BTC", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypeLimit,
+		Volume: dec("0.5"), Price: dec("50010000"), TimeInForce: upbit.TimeInForceFOK,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if order.State != "cancel" || !order.ExecutedVolume.IsZero() {
+		t.Errorf("Expected unfilled cancelled FOK order, got %+v", order)
+	}
+	if _, locked := srv.Balance("KRW"); !locked.IsZero() {
+		t.Errorf("Expected nothing locked, got %s", locked)
+	}
+}
+
+func TestTickRules(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.AddMarket(upbit.Market{Market: "KRW-ETH"})
+	srv.SetBalance("KRW", dec("10000000"))
+
+	limit := func(price string) error {
+		_, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+			Market: "KRW-ETH", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypeLimit,
+			Volume: dec("1"), Price: dec(price),
+		})
+		return err
+	}
+
+	for _, price := range []string{"1500500", "5005", "150.1"} {
+		if err := limit(price); err != nil && !errors.Is(err, upbit.ErrUnderMinTotalBid) {
+			t.Errorf("Expected %s KRW to be on tick, got %v", price, err)
+		}
+	}
+	expectAPIError(t, limit("1500100"), upbit.ErrInvalidPrice)
+
+	srv.SetTickTable("KRW", upbit.TickTable{{MinPrice: dec("0"), TickSize: dec("10")}})
+	expectAPIError(t, limit("5005"), upbit.ErrInvalidPrice)
+}
+
+func TestAuthentication(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	client := upbit.NewClient("access", "wrong")
+	client.SetBaseURL(srv.URL())
+	_, err := client.GetAccounts()
+	expectAPIError(t, err, upbit.ErrJWTVerificationFailed)
+
+	if apiErr := srv.authenticate("Bearer x", "market=KRW-BTC"); apiErr == nil {
+		t.Error("Expected malformed token to be rejected")
+	}
+}
+
+func TestWithdrawAndDeposit(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetWithdrawFee("BTC", dec("0.0005"))
+	srv.Deposit("BTC", dec("1"))
+
+	deposits, err := client.GetDeposits("BTC", "", nil, nil, 0, 0, "")
+	if err != nil {
+		t.Fatalf("GetDeposits failed: %v", err)
+	}
+	if len(deposits) != 1 || deposits[0].Amount.String() != "1" {
+		t.Errorf("Unexpected deposits %+v", deposits)
+	}
+
+	w, err := client.WithdrawCoin(&upbit.WithdrawCoinRequest{
+		Currency: "BTC", NetType: "BTC", Amount: dec("0.5"), Address: "addr",
+	})
+	if err != nil {
+		t.Fatalf("WithdrawCoin failed: %v", err)
+	}
+	if available, locked := srv.Balance("BTC"); available.String() != "0.4995" || locked.String() != "0.5005" {
+		t.Errorf("Unexpected BTC balance %s/%s", available, locked)
+	}
+
+	if !srv.CompleteWithdraw(w.UUID) {
+		t.Fatal("Expected pending withdrawal to complete")
+	}
+	got, err := client.GetWithdraw(w.UUID)
+	if err != nil {
+		t.Fatalf("GetWithdraw failed: %v", err)
+	}
+	if got.State != "DONE" {
+		t.Errorf("Expected DONE, got %s", got.State)
+	}
+	if _, locked := srv.Balance("BTC"); !locked.IsZero() {
+		t.Errorf("Expected nothing locked, got %s", locked)
+	}
+
+	_, err = client.WithdrawKRW(dec("1000"), "")
+	expectAPIError(t, err, upbit.ErrInsufficientFunds)
+}
+
+func TestOrderQueries(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.SetBalance("KRW", dec("100000000"))
+	srv.SetClock(func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) })
+
+	var uuids []string
+	for _, price := range []string{"49000000", "48000000"} {
+		order, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+			Market: "KRW-BTC", Side: upbit.OrderSideBid, OrdType: upbit.OrderTypeLimit,
+			Volume: dec("0.01"), Price: dec(price),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		uuids = append(uuids, order.UUID)
+	}
+	if _, err := client.CancelOrder(uuids[1]); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	// Array parameters must hash in their unescaped form to pass the server.
+	orders, err := client.GetOrders(&upbit.GetOrdersRequest{
+		UUIDs:  uuids,
+		States: []upbit.OrderState{upbit.OrderStateWait, upbit.OrderStateWatch},
+	})
+	if err != nil {
+		t.Fatalf("GetOrders failed: %v", err)
+	}
+	if len(orders) != 1 || orders[0].UUID != uuids[0] {
+		t.Errorf("Expected the waiting order, got %+v", orders)
+	}
+
+	open, err := client.GetOpenOrders(&upbit.OpenOrdersRequest{
+		Market: "KRW-BTC",
+		States: []upbit.OrderState{upbit.OrderStateWait, upbit.OrderStateWatch},
+	})
+	if err != nil {
+		t.Fatalf("GetOpenOrders failed: %v", err)
+	}
+	if len(open) != 1 || open[0].UUID != uuids[0] {
+		t.Errorf("Expected the waiting order, got %+v", open)
+	}
+
+	// The month-long range is split into windows the server accepts.
+	closed, err := client.GetClosedOrdersRange(&upbit.ClosedOrdersRequest{
+		Market: "KRW-BTC",
+		States: []upbit.OrderState{upbit.OrderStateCancel},
+		Start:  time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("GetClosedOrdersRange failed: %v", err)
+	}
+	if len(closed) != 1 || closed[0].UUID != uuids[1] {
+		t.Errorf("Expected the cancelled order, got %+v", closed)
+	}
+}
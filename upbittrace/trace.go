@@ -0,0 +1,94 @@
+// Package upbittrace emits a span for every API call made by an
+// upbit.Client. Spans are created through the small Tracer interface, which
+// an adapter for OpenTelemetry or any other tracing library can implement
+// without this package depending on it.
+//
+//	client := upbit.NewClient(accessKey, secretKey, upbit.WithHooks(upbittrace.NewHook(tracer)))
+//
+// Spans are named "upbit <method> <endpoint>", e.g. "upbit POST /orders",
+// and carry these attributes:
+//
+//	http.request.method        request method
+//	upbit.endpoint             path below the base URL
+//	upbit.rate_limit_group     rate limit group of the endpoint
+//	upbit.authenticated        whether the request was signed
+//	upbit.attempts             number of attempts including retries
+//	http.response.status_code  status of the last response, if any
+//	upbit.rate_limit.remaining requests left in the current second, if reported
+//	upbit.error_name           Upbit error name of a failed call
+package upbittrace
+
+import (
+	"context"
+	"errors"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span and returns a context containing it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// spanKey is the context key of the span started by a Hook. Each Hook has
+// its own, so hooks installed on the same client keep their spans apart. It
+// is not zero-size, as pointers to distinct zero-size values may be equal.
+type spanKey struct{ _ byte }
+
+// Hook is an upbit.Hook that traces API calls.
+type Hook struct {
+	tracer Tracer
+	key    *spanKey
+}
+
+// NewHook creates a hook that starts spans with tracer.
+func NewHook(tracer Tracer) *Hook {
+	return &Hook{tracer: tracer, key: new(spanKey)}
+}
+
+// RequestStart implements upbit.Hook.
+func (h *Hook) RequestStart(ctx context.Context, info upbit.RequestInfo) context.Context {
+	ctx, span := h.tracer.Start(ctx, "upbit "+info.Method+" "+info.Endpoint)
+	span.SetAttribute("http.request.method", info.Method)
+	span.SetAttribute("upbit.endpoint", info.Endpoint)
+	span.SetAttribute("upbit.rate_limit_group", string(info.Group))
+	span.SetAttribute("upbit.authenticated", info.Authenticated)
+	return context.WithValue(ctx, h.key, span)
+}
+
+// RequestError implements upbit.Hook.
+func (h *Hook) RequestError(ctx context.Context, info upbit.RequestInfo, err error) {
+	span, ok := ctx.Value(h.key).(Span)
+	if !ok {
+		return
+	}
+	span.RecordError(err)
+	var apiErr *upbit.APIError
+	if errors.As(err, &apiErr) {
+		span.SetAttribute("upbit.error_name", apiErr.Err.Name)
+	}
+}
+
+// RequestEnd implements upbit.Hook.
+func (h *Hook) RequestEnd(ctx context.Context, info upbit.RequestInfo, result upbit.RequestResult) {
+	span, ok := ctx.Value(h.key).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("upbit.attempts", info.Attempt)
+	if result.StatusCode != 0 {
+		span.SetAttribute("http.response.status_code", result.StatusCode)
+	}
+	if result.HasRemaining {
+		span.SetAttribute("upbit.rate_limit.remaining", result.Remaining.Sec)
+	}
+	span.End()
+}
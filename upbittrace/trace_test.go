@@ -0,0 +1,126 @@
+package upbittrace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	upbit "github.com/th-release/go-upbit-sdk"
+)
+
+type parentKey struct{}
+
+type testSpan struct {
+	name   string
+	parent any
+	attrs  map[string]any
+	errs   []error
+	ended  bool
+}
+
+func (s *testSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)              { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                               { s.ended = true }
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &testSpan{name: name, parent: ctx.Value(parentKey{}), attrs: make(map[string]any)}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, parentKey{}, s), s
+}
+
+func TestHook(t *testing.T) {
+	var sawSpan bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Remaining-Req", "group=order; min=59; sec=7")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"name":"under_min_total_bid","message":"too small"}}`))
+	}))
+	defer server.Close()
+
+	// Middleware sees the context returned by the tracer, so it can
+	// propagate the span to the server.
+	propagate := func(next upbit.RoundTripFunc) upbit.RoundTripFunc {
+		return func(req *http.Request, info upbit.RequestInfo) (*http.Response, error) {
+			_, sawSpan = req.Context().Value(parentKey{}).(*testSpan)
+			return next(req, info)
+		}
+	}
+
+	tracer := &testTracer{}
+	client := upbit.NewClient("access", "secret",
+		upbit.WithBaseURL(server.URL+"/v1"),
+		upbit.WithHooks(NewHook(tracer)),
+		upbit.WithMiddleware(propagate),
+	)
+
+	_, err := client.PlaceOrder(&upbit.PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    upbit.OrderSideBid,
+		OrdType: upbit.OrderTypePrice,
+		Price:   upbit.DecimalFromInt(100),
+	})
+	if !errors.Is(err, upbit.ErrUnderMinTotalBid) {
+		t.Fatalf("Expected under_min_total_bid, got %v", err)
+	}
+	if !sawSpan {
+		t.Error("Expected the span in the request context")
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "upbit POST /orders" || !span.ended {
+		t.Errorf("Unexpected span %s (ended %v)", span.name, span.ended)
+	}
+	want := map[string]any{
+		"http.request.method":        "POST",
+		"upbit.endpoint":             "/orders",
+		"upbit.rate_limit_group":     "order",
+		"upbit.authenticated":        true,
+		"upbit.attempts":             1,
+		"http.response.status_code":  400,
+		"upbit.rate_limit.remaining": 7,
+		"upbit.error_name":           "under_min_total_bid",
+	}
+	for k, v := range want {
+		if span.attrs[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, span.attrs[k])
+		}
+	}
+	if len(span.errs) != 1 || !errors.Is(span.errs[0], upbit.ErrUnderMinTotalBid) {
+		t.Errorf("Expected the call error to be recorded, got %v", span.errs)
+	}
+}
+
+func TestHooksKeepSpansApart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	first, second := &testTracer{}, &testTracer{}
+	client := upbit.NewClient("access", "secret",
+		upbit.WithBaseURL(server.URL+"/v1"),
+		upbit.WithHooks(NewHook(first), NewHook(second)),
+	)
+	if _, err := client.GetMarkets(false); err != nil {
+		t.Fatalf("GetMarkets failed: %v", err)
+	}
+
+	for i, tracer := range []*testTracer{first, second} {
+		if len(tracer.spans) != 1 || !tracer.spans[0].ended {
+			t.Errorf("Hook %d: expected 1 ended span, got %+v", i, tracer.spans)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package upbit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// TickRule is one price band of a tick size table.
+type TickRule struct {
+	MinPrice Decimal // Lowest price of the band (inclusive)
+	TickSize Decimal // Price increment within the band
+}
+
+// TickTable is a tick size table ordered by descending MinPrice. The last
+// rule applies to all prices below the other bands.
+type TickTable []TickRule
+
+// TickSize returns the tick size that applies to price.
+func (t TickTable) TickSize(price Decimal) Decimal {
+	for _, rule := range t {
+		if price.Cmp(rule.MinPrice) >= 0 {
+			return rule.TickSize
+		}
+	}
+	if len(t) == 0 {
+		return Decimal{}
+	}
+	return t[len(t)-1].TickSize
+}
+
+func tickTable(rules ...[2]string) TickTable {
+	table := make(TickTable, len(rules))
+	for i, r := range rules {
+		table[i] = TickRule{MinPrice: MustParseDecimal(r[0]), TickSize: MustParseDecimal(r[1])}
+	}
+	return table
+}
+
+var (
+	krwTicks = tickTable(
+		[2]string{"2000000", "1000"},
+		[2]string{"1000000", "500"},
+		[2]string{"500000", "100"},
+		[2]string{"100000", "50"},
+		[2]string{"10000", "10"},
+		[2]string{"1000", "1"},
+		[2]string{"100", "0.1"},
+		[2]string{"10", "0.01"},
+		[2]string{"1", "0.001"},
+		[2]string{"0.1", "0.0001"},
+		[2]string{"0.01", "0.00001"},
+		[2]string{"0.001", "0.000001"},
+		[2]string{"0.0001", "0.0000001"},
+		[2]string{"0", "0.00000001"},
+	)
+	btcTicks = tickTable(
+		[2]string{"0", "0.00000001"},
+	)
+	usdtTicks = tickTable(
+		[2]string{"10", "0.01"},
+		[2]string{"1", "0.001"},
+		[2]string{"0.1", "0.0001"},
+		[2]string{"0.01", "0.00001"},
+		[2]string{"0.001", "0.000001"},
+		[2]string{"0.0001", "0.0000001"},
+		[2]string{"0", "0.00000001"},
+	)
+	sgdTicks = tickTable(
+		[2]string{"1000", "1"},
+		[2]string{"100", "0.1"},
+		[2]string{"10", "0.01"},
+		[2]string{"1", "0.001"},
+		[2]string{"0.1", "0.0001"},
+		[2]string{"0", "0.00001"},
+	)
+	idrTicks = tickTable(
+		[2]string{"100000000", "10000"},
+		[2]string{"10000000", "1000"},
+		[2]string{"1000000", "100"},
+		[2]string{"100000", "10"},
+		[2]string{"10000", "1"},
+		[2]string{"1000", "0.1"},
+		[2]string{"100", "0.01"},
+		[2]string{"0", "0.001"},
+	)
+	thbTicks = tickTable(
+		[2]string{"100000", "10"},
+		[2]string{"10000", "1"},
+		[2]string{"1000", "0.1"},
+		[2]string{"100", "0.01"},
+		[2]string{"10", "0.001"},
+		[2]string{"1", "0.0001"},
+		[2]string{"0", "0.00001"},
+	)
+)
+
+var (
+	tickTablesMu sync.RWMutex
+	tickTables   = map[Region]map[string]TickTable{
+		RegionKR: {"KRW": krwTicks, "BTC": btcTicks, "USDT": usdtTicks},
+		RegionSG: {"SGD": sgdTicks, "BTC": btcTicks, "USDT": usdtTicks},
+		RegionID: {"IDR": idrTicks, "BTC": btcTicks, "USDT": usdtTicks},
+		RegionTH: {"THB": thbTicks, "BTC": btcTicks, "USDT": usdtTicks},
+	}
+)
+
+// RegisterTickTable sets the tick size table of a quote currency on Upbit
+// Korea, replacing any existing one. Use it if Upbit changes the tick policy
+// of a market.
+func RegisterTickTable(quote string, table TickTable) {
+	RegionKR.RegisterTickTable(quote, table)
+}
+
+// RegisterTickTable sets the tick size table of a quote currency in r,
+// replacing any existing one.
+func (r Region) RegisterTickTable(quote string, table TickTable) {
+	tickTablesMu.Lock()
+	defer tickTablesMu.Unlock()
+	if tickTables[r] == nil {
+		tickTables[r] = make(map[string]TickTable)
+	}
+	tickTables[r][strings.ToUpper(quote)] = slices.Clone(table)
+}
+
+// quoteCurrency returns the quote currency of a market code like "KRW-BTC".
+func quoteCurrency(market string) string {
+	quote, _, _ := strings.Cut(market, "-")
+	return strings.ToUpper(quote)
+}
+
+// TickSize returns the tick size of a market on Upbit Korea at the given
+// price.
+func TickSize(market string, price Decimal) (Decimal, error) {
+	return RegionKR.TickSize(market, price)
+}
+
+// TickSize returns the tick size of a market in r at the given price.
+func (r Region) TickSize(market string, price Decimal) (Decimal, error) {
+	tickTablesMu.RLock()
+	table, ok := tickTables[r][quoteCurrency(market)]
+	tickTablesMu.RUnlock()
+	if !ok {
+		return Decimal{}, fmt.Errorf("upbit: no tick size table for market %q in region %s", market, r)
+	}
+	return table.TickSize(price), nil
+}
+
+// TickRounding selects how RoundToTick moves prices that are off-tick.
+type TickRounding int
+
+const (
+	TickRoundNearest TickRounding = iota // Nearest tick, halves rounded up
+	TickRoundDown                        // Next lower tick
+	TickRoundUp                          // Next higher tick
+)
+
+// roundToStep rounds d to a multiple of step.
+func roundToStep(d, step Decimal, mode roundingMode) Decimal {
+	dc, sc, exp := align(d, step)
+	q := quoRound(dc, sc, mode)
+	return Decimal{coef: q.Mul(q, sc), exp: exp}
+}
+
+// RoundToTick rounds price to a valid tick of market on Upbit Korea.
+func RoundToTick(market string, price Decimal, rounding TickRounding) (Decimal, error) {
+	return RegionKR.RoundToTick(market, price, rounding)
+}
+
+// RoundToTick rounds price to a valid tick of market in r.
+func (r Region) RoundToTick(market string, price Decimal, rounding TickRounding) (Decimal, error) {
+	tick, err := r.TickSize(market, price)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	switch rounding {
+	case TickRoundDown:
+		return roundToStep(price, tick, roundFloor), nil
+	case TickRoundUp:
+		return roundToStep(price, tick, roundCeil), nil
+	default:
+		return roundToStep(price, tick, roundHalfAway), nil
+	}
+}
+
+// IsValidTick reports whether price is a multiple of market's tick size on
+// Upbit Korea.
+func IsValidTick(market string, price Decimal) bool {
+	return RegionKR.IsValidTick(market, price)
+}
+
+// IsValidTick reports whether price is a multiple of market's tick size in r.
+func (r Region) IsValidTick(market string, price Decimal) bool {
+	tick, err := r.TickSize(market, price)
+	if err != nil || tick.IsZero() {
+		return false
+	}
+	return roundToStep(price, tick, roundTruncate).Equal(price)
+}
+
+// OrderValidationError reports an order, or a withdrawal in dry-run mode,
+// rejected by client-side validation before it was sent.
+type OrderValidationError struct {
+	Code    ErrorCode // Error name the server would return, e.g. ErrInvalidPrice
+	Field   string    // Offending request field
+	Message string
+}
+
+func (e *OrderValidationError) Error() string {
+	return fmt.Sprintf("upbit order validation error: %s - %s: %s", e.Code, e.Field, e.Message)
+}
+
+// Is reports whether target is the ErrorCode of e.
+func (e *OrderValidationError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && e.Code == code
+}
+
+func invalidOrder(code ErrorCode, field, format string, args ...any) error {
+	return &OrderValidationError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidateOrder checks req against the market code and tick size rules of
+// Upbit Korea and, when chance is non-nil, against the market's order types,
+// minimum totals and the available balance including reserved fees.
+func ValidateOrder(req *PlaceOrderRequest, chance *OrderChance) error {
+	return RegionKR.ValidateOrder(req, chance)
+}
+
+// ValidateOrder is like the package-level ValidateOrder but applies the
+// market code and tick size rules of r.
+func (r Region) ValidateOrder(req *PlaceOrderRequest, chance *OrderChance) error {
+	if req.Market == "" {
+		return invalidOrder(ErrInvalidMarket, "Market", "market is required")
+	}
+	if err := r.ValidateMarket(req.Market); err != nil {
+		return err
+	}
+	if req.Side != OrderSideBid && req.Side != OrderSideAsk {
+		return invalidOrder(ErrInvalidParameter, "Side", "unknown side %q", req.Side)
+	}
+	if req.Volume.Sign() < 0 {
+		return invalidOrder(ErrInvalidVolume, "Volume", "volume must be positive")
+	}
+	if req.Price.Sign() < 0 {
+		return invalidOrder(ErrInvalidPrice, "Price", "price must be positive")
+	}
+
+	switch req.OrdType {
+	case OrderTypeLimit:
+		if req.Price.IsZero() {
+			return invalidOrder(ErrInvalidPrice, "Price", "price is required for limit orders")
+		}
+		if req.Volume.IsZero() {
+			return invalidOrder(ErrInvalidVolume, "Volume", "volume is required for limit orders")
+		}
+		if _, err := r.TickSize(req.Market, req.Price); err != nil {
+			return invalidOrder(ErrInvalidMarket, "Market", "%v", err)
+		}
+		if !r.IsValidTick(req.Market, req.Price) {
+			tick, _ := r.TickSize(req.Market, req.Price)
+			return invalidOrder(ErrInvalidPrice, "Price", "price %s is not a multiple of tick size %s", req.Price, tick)
+		}
+	case OrderTypePrice:
+		if req.Side != OrderSideBid {
+			return invalidOrder(ErrInvalidParameter, "OrdType", "price orders must be bids")
+		}
+		if req.Price.IsZero() {
+			return invalidOrder(ErrInvalidPrice, "Price", "price (total funds) is required for price orders")
+		}
+		if !req.Volume.IsZero() {
+			return invalidOrder(ErrInvalidVolume, "Volume", "volume must be empty for price orders")
+		}
+	case OrderTypeMarket:
+		if req.Side != OrderSideAsk {
+			return invalidOrder(ErrInvalidParameter, "OrdType", "market orders must be asks")
+		}
+		if req.Volume.IsZero() {
+			return invalidOrder(ErrInvalidVolume, "Volume", "volume is required for market orders")
+		}
+		if !req.Price.IsZero() {
+			return invalidOrder(ErrInvalidPrice, "Price", "price must be empty for market orders")
+		}
+	case OrderTypeBest:
+		if req.TimeInForce != TimeInForceIOC && req.TimeInForce != TimeInForceFOK {
+			return invalidOrder(ErrInvalidParameter, "TimeInForce", "best orders require ioc or fok")
+		}
+		if req.Side == OrderSideBid && req.Price.IsZero() {
+			return invalidOrder(ErrInvalidPrice, "Price", "price (total funds) is required for best bids")
+		}
+		if req.Side == OrderSideAsk && req.Volume.IsZero() {
+			return invalidOrder(ErrInvalidVolume, "Volume", "volume is required for best asks")
+		}
+	default:
+		return invalidOrder(ErrInvalidParameter, "OrdType", "unknown order type %q", req.OrdType)
+	}
+
+	if err := validateOrderOptions("", req.OrdType, req.TimeInForce, req.SMPType); err != nil {
+		return err
+	}
+
+	if chance == nil {
+		return nil
+	}
+	return validateOrderChance(req, chance)
+}
+
+// validateOrderOptions checks that the time in force and self-match
+// prevention mode are known and compatible with the order type. It needs no
+// market data, so orders are always checked with it. prefix is prepended to
+// the reported field names.
+func validateOrderOptions(prefix string, ordType OrderType, tif TimeInForce, smp SMPType) error {
+	switch tif {
+	case "":
+	case TimeInForceIOC, TimeInForceFOK:
+		if ordType != OrderTypeLimit && ordType != OrderTypeBest {
+			return invalidOrder(ErrInvalidParameter, prefix+"TimeInForce", "%s requires a limit or best order", tif)
+		}
+	case TimeInForcePostOnly:
+		if ordType != OrderTypeLimit {
+			return invalidOrder(ErrInvalidParameter, prefix+"TimeInForce", "post_only requires a limit order")
+		}
+		if smp != "" {
+			return invalidOrder(ErrInvalidParameter, prefix+"SMPType", "post_only cannot be combined with self-match prevention")
+		}
+	default:
+		return invalidOrder(ErrInvalidParameter, prefix+"TimeInForce", "unknown time in force %q", tif)
+	}
+
+	switch smp {
+	case "", SMPTypeCancelMaker, SMPTypeCancelTaker, SMPTypeReduce:
+	default:
+		return invalidOrder(ErrInvalidParameter, prefix+"SMPType", "unknown self-match prevention mode %q", smp)
+	}
+	return nil
+}
+
+// validateOrderChance checks req against the constraints of GetOrderChance.
+func validateOrderChance(req *PlaceOrderRequest, chance *OrderChance) error {
+	if m := chance.Market; m != nil {
+		if m.State != "" && m.State != "active" {
+			return invalidOrder(ErrInvalidMarket, "Market", "market %s is %s", req.Market, m.State)
+		}
+		types := m.BidTypes
+		if req.Side == OrderSideAsk {
+			types = m.AskTypes
+		}
+		if len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool {
+			return t == string(req.OrdType) || strings.HasPrefix(t, string(req.OrdType)+"_")
+		}) {
+			return invalidOrder(ErrInvalidParameter, "OrdType", "%s %s orders are not supported on %s", req.OrdType, req.Side, req.Market)
+		}
+	}
+
+	// The total is known up front for limit orders and bids by funds.
+	var total Decimal
+	switch {
+	case req.OrdType == OrderTypeLimit:
+		total = req.Price.Mul(req.Volume)
+	case req.Side == OrderSideBid:
+		total = req.Price
+	}
+
+	if req.Side == OrderSideBid {
+		if m := chance.Market; m != nil && m.Bid != nil && total.LessThan(m.Bid.MinTotal) {
+			return invalidOrder(ErrUnderMinTotalBid, "Price", "order total %s is below the minimum %s", total, m.Bid.MinTotal)
+		}
+		if acc := chance.BidAccount; acc != nil {
+			required := total.Add(total.Mul(chance.BidFee))
+			if required.GreaterThan(acc.Balance) {
+				return invalidOrder(ErrInsufficientFunds, "Price", "order requires %s %s including fees but %s is available", required, acc.Currency, acc.Balance)
+			}
+		}
+		return nil
+	}
+
+	if m := chance.Market; m != nil && m.Ask != nil && !total.IsZero() && total.LessThan(m.Ask.MinTotal) {
+		return invalidOrder(ErrUnderMinTotalAsk, "Volume", "order total %s is below the minimum %s", total, m.Ask.MinTotal)
+	}
+	if acc := chance.AskAccount; acc != nil && req.Volume.GreaterThan(acc.Balance) {
+		return invalidOrder(ErrInsufficientFunds, "Volume", "order requires %s %s but %s is available", req.Volume, acc.Currency, acc.Balance)
+	}
+	return nil
+}
+
+// validateOrder fetches the order chance of req's market and validates req,
+// returning the chance it was checked against.
+func (c *Client) validateOrder(ctx context.Context, req *PlaceOrderRequest) (*OrderChance, error) {
+	if err := c.region.ValidateOrder(req, nil); err != nil {
+		return nil, err
+	}
+	chance, err := c.GetOrderChanceContext(ctx, req.Market)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.region.ValidateOrder(req, chance); err != nil {
+		return nil, err
+	}
+	return chance, nil
+}
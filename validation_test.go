@@ -0,0 +1,222 @@
+package upbit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTickSize(t *testing.T) {
+	tests := []struct {
+		market, price, want string
+	}{
+		{"KRW-BTC", "50000000", "1000"},
+		{"KRW-ETH", "999999", "100"},
+		{"KRW-ETH", "1500500", "500"},
+		{"KRW-ETC", "5005", "1"},
+		{"KRW-XRP", "750", "0.1"},
+		{"KRW-XRP", "150.1", "0.1"},
+		{"KRW-DOGE", "99.9", "0.01"},
+		{"KRW-SHIB", "0.0123", "0.00001"},
+		{"BTC-ETH", "0.05", "0.00000001"},
+		{"USDT-BTC", "65000.5", "0.01"},
+	}
+	for _, tt := range tests {
+		got, err := TickSize(tt.market, MustParseDecimal(tt.price))
+		if err != nil {
+			t.Errorf("TickSize(%s, %s): unexpected error %v", tt.market, tt.price, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("TickSize(%s, %s) = %s, want %s", tt.market, tt.price, got, tt.want)
+		}
+	}
+
+	if _, err := TickSize("XYZ-BTC", DecimalFromInt(1)); err == nil {
+		t.Error("Expected error for unknown quote currency")
+	}
+}
+
+func TestRoundToTick(t *testing.T) {
+	price := MustParseDecimal("50012345")
+	tests := []struct {
+		rounding TickRounding
+		want     string
+	}{
+		{TickRoundDown, "50012000"},
+		{TickRoundUp, "50013000"},
+		{TickRoundNearest, "50012000"},
+	}
+	for _, tt := range tests {
+		got, err := RoundToTick("KRW-BTC", price, tt.rounding)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("RoundToTick(%d) = %s, want %s", tt.rounding, got, tt.want)
+		}
+	}
+
+	got, _ := RoundToTick("KRW-XRP", MustParseDecimal("99.995"), TickRoundUp)
+	if got.String() != "100" {
+		t.Errorf("Expected 100, got %s", got)
+	}
+
+	if !IsValidTick("KRW-BTC", MustParseDecimal("50012000")) || IsValidTick("KRW-BTC", price) {
+		t.Error("Unexpected IsValidTick result")
+	}
+}
+
+func testOrderChance() *OrderChance {
+	var chance OrderChance
+	json.Unmarshal([]byte(`{
+		"bid_fee": "0.0005",
+		"ask_fee": "0.0005",
+		"market": {
+			"id": "KRW-BTC",
+			"bid_types": ["limit", "price", "best_ioc", "best_fok"],
+			"ask_types": ["limit", "market", "best_ioc", "best_fok"],
+			"bid": {"currency": "KRW", "min_total": "5000"},
+			"ask": {"currency": "BTC", "min_total": "5000"},
+			"state": "active"
+		},
+		"bid_account": {"currency": "KRW", "balance": "100000"},
+		"ask_account": {"currency": "BTC", "balance": "0.001"}
+	}`), &chance)
+	return &chance
+}
+
+func withOptions(req *PlaceOrderRequest, tif TimeInForce, smp SMPType) *PlaceOrderRequest {
+	req.TimeInForce = tif
+	req.SMPType = smp
+	return req
+}
+
+func TestValidateOrder(t *testing.T) {
+	limit := func(side OrderSide, price, volume string) *PlaceOrderRequest {
+		return &PlaceOrderRequest{
+			Market:  "KRW-BTC",
+			Side:    side,
+			OrdType: OrderTypeLimit,
+			Price:   MustParseDecimal(price),
+			Volume:  MustParseDecimal(volume),
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  *PlaceOrderRequest
+		code ErrorCode
+	}{
+		{"valid bid", limit(OrderSideBid, "50000000", "0.001"), ""},
+		{"valid ask", limit(OrderSideAsk, "50000000", "0.001"), ""},
+		{"off tick", limit(OrderSideBid, "50000500", "0.001"), ErrInvalidPrice},
+		{"under min bid", limit(OrderSideBid, "50000000", "0.00001"), ErrUnderMinTotalBid},
+		{"under min ask", limit(OrderSideAsk, "50000000", "0.00001"), ErrUnderMinTotalAsk},
+		// 50000000 * 0.002 = 100000, plus the fee exceeds the balance.
+		{"fees exceed balance", limit(OrderSideBid, "50000000", "0.002"), ErrInsufficientFunds},
+		{"volume exceeds balance", limit(OrderSideAsk, "50000000", "0.002"), ErrInsufficientFunds},
+		{"market bid", &PlaceOrderRequest{Market: "KRW-BTC", Side: OrderSideBid, OrdType: OrderTypeMarket, Volume: DecimalFromInt(1)}, ErrInvalidParameter},
+		{"best without tif", &PlaceOrderRequest{Market: "KRW-BTC", Side: OrderSideBid, OrdType: OrderTypeBest, Price: DecimalFromInt(10000)}, ErrInvalidParameter},
+		{"price bid", &PlaceOrderRequest{Market: "KRW-BTC", Side: OrderSideBid, OrdType: OrderTypePrice, Price: DecimalFromInt(10000)}, ""},
+		{"post only", withOptions(limit(OrderSideBid, "50000000", "0.001"), TimeInForcePostOnly, ""), ""},
+		{"post only market", &PlaceOrderRequest{Market: "KRW-BTC", Side: OrderSideAsk, OrdType: OrderTypeMarket, Volume: MustParseDecimal("0.001"), TimeInForce: TimeInForcePostOnly}, ErrInvalidParameter},
+		{"post only with smp", withOptions(limit(OrderSideBid, "50000000", "0.001"), TimeInForcePostOnly, SMPTypeCancelMaker), ErrInvalidParameter},
+		{"ioc price bid", &PlaceOrderRequest{Market: "KRW-BTC", Side: OrderSideBid, OrdType: OrderTypePrice, Price: DecimalFromInt(10000), TimeInForce: TimeInForceIOC}, ErrInvalidParameter},
+		{"smp reduce", withOptions(limit(OrderSideAsk, "50000000", "0.001"), TimeInForceIOC, SMPTypeReduce), ""},
+		{"unknown smp", withOptions(limit(OrderSideAsk, "50000000", "0.001"), "", "cancel_both"), ErrInvalidParameter},
+	}
+
+	chance := testOrderChance()
+	for _, tt := range tests {
+		err := ValidateOrder(tt.req, chance)
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		var verr *OrderValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: expected *OrderValidationError, got %v", tt.name, err)
+			continue
+		}
+		if verr.Code != tt.code {
+			t.Errorf("%s: expected code %s, got %s", tt.name, tt.code, verr.Code)
+		}
+	}
+}
+
+func TestPlaceOrderValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/orders/chance":
+			json.NewEncoder(w).Encode(testOrderChance())
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("access", "secret")
+	client.SetBaseURL(server.URL + "/v1")
+	client.SetOrderValidation(true)
+
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		OrdType: OrderTypeLimit,
+		Price:   MustParseDecimal("50000001"),
+		Volume:  MustParseDecimal("0.001"),
+	})
+	var verr *OrderValidationError
+	if !errors.As(err, &verr) || verr.Code != ErrInvalidPrice {
+		t.Errorf("Expected invalid_price validation error, got %v", err)
+	}
+}
+
+func TestOrderOptionsAlwaysChecked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	// Order validation is off, but incompatible options never reach the
+	// exchange.
+	client := NewClient("access", "secret", WithBaseURL(server.URL+"/v1"))
+
+	var verr *OrderValidationError
+	_, err := client.PlaceOrder(&PlaceOrderRequest{
+		Market:      "KRW-BTC",
+		Side:        OrderSideAsk,
+		OrdType:     OrderTypeMarket,
+		Volume:      MustParseDecimal("0.001"),
+		TimeInForce: TimeInForcePostOnly,
+	})
+	if !errors.As(err, &verr) || verr.Field != "TimeInForce" {
+		t.Errorf("Expected a TimeInForce validation error, got %v", err)
+	}
+
+	_, err = client.PlaceOrder(withOptions(&PlaceOrderRequest{
+		Market:  "KRW-BTC",
+		Side:    OrderSideBid,
+		OrdType: OrderTypeLimit,
+		Price:   DecimalFromInt(50000000),
+		Volume:  MustParseDecimal("0.001"),
+	}, TimeInForcePostOnly, SMPTypeReduce))
+	if !errors.As(err, &verr) || verr.Field != "SMPType" {
+		t.Errorf("Expected an SMPType validation error, got %v", err)
+	}
+
+	_, err = client.ReplaceOrder(&ReplaceOrderRequest{
+		PrevOrderUUID:  "prev-uuid",
+		NewOrdType:     OrderTypeBest,
+		NewVolume:      MustParseDecimal("0.001"),
+		NewTimeInForce: TimeInForcePostOnly,
+	})
+	if !errors.As(err, &verr) || verr.Field != "NewTimeInForce" {
+		t.Errorf("Expected a NewTimeInForce validation error, got %v", err)
+	}
+}